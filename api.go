@@ -0,0 +1,92 @@
+package daily
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// API is the set of Client methods, so code depending on Daily can be tested
+// with a mock. Client is its only implementation. The narrower interfaces it
+// is made of can be used for code needing only part of it.
+type API interface {
+	DomainService
+	RoomService
+	MeetingTokenService
+	RecordingService
+	MeetingService
+	Close() error
+}
+
+var _ API = (*Client)(nil)
+
+// DomainService covers the domain configuration methods of Client.
+type DomainService interface {
+	VerifyCredentials(ctx context.Context) error
+	Probe(ctx context.Context) (ProbeResult, error)
+	GetDomainConfig(ctx context.Context) (*DomainConfig, error)
+	SetDomainConfig(ctx context.Context, req *Config) (*DomainConfig, error)
+	CachedDomainConfig(ctx context.Context) (*DomainConfig, error)
+	RefreshDomainConfig(ctx context.Context) (*DomainConfig, error)
+	GetRoomConfigDefaults(ctx context.Context) (*RoomConfig, error)
+}
+
+// RoomService covers the room methods of Client.
+type RoomService interface {
+	ListRooms(ctx context.Context, req *ListRoomsRequest) (*ListRoomsResponse, error)
+	ListRoomsMatching(ctx context.Context, predicate func(Room) bool) ([]Room, error)
+	CreateRoom(ctx context.Context, req *CreateRoomRequest) (*CreateRoomResponse, error)
+	CreateRoomFromTemplate(ctx context.Context, t *RoomTemplate, overrides ...RoomOption) (*CreateRoomResponse, error)
+	CreateTemporaryRoom(ctx context.Context, name string, ttl time.Duration, req *CreateRoomRequest) (*CreateRoomResponse, error)
+	GetOrCreateRoom(ctx context.Context, name string, req *CreateRoomRequest) (*Room, error)
+	GetRoom(ctx context.Context, name string) (*GetRoomResponse, error)
+	GetRoomByID(ctx context.Context, id string) (*Room, error)
+	GetRoomConditional(ctx context.Context, name, etag string) (*GetRoomResponse, string, bool, error)
+	UpdateRoom(ctx context.Context, name string, req *UpdateRoomRequest) (*UpdateRoomResponse, error)
+	PatchRoom(ctx context.Context, name string, mutate func(*RoomConfig)) (*UpdateRoomResponse, error)
+	PatchRoomIf(ctx context.Context, name string, precondition func(*Room) bool, mutate func(*RoomConfig)) (*UpdateRoomResponse, error)
+	RecreateRoom(ctx context.Context, oldName, newName string) (*Room, error)
+	DeleteRoom(ctx context.Context, name string) error
+	GetRoomPresence(ctx context.Context, name string) (*GetRoomPresenceResponse, error)
+	GetRoomWithPresence(ctx context.Context, name string) (*Room, []PresenceParticipant, error)
+	SendAppMessage(ctx context.Context, roomName string, msg json.RawMessage, recipients []string) error
+}
+
+// MeetingTokenService covers the meeting token methods of Client.
+type MeetingTokenService interface {
+	CreateMeetingToken(ctx context.Context, req *CreateMeetingTokenRequest) (*CreateMeetingTokenResponse, error)
+	CreateMeetingTokens(ctx context.Context, reqs []*CreateMeetingTokenRequest, concurrency int) ([]*CreateMeetingTokenResponse, []error)
+	CreateJoinURL(ctx context.Context, roomName string, token *MeetingToken) (string, error)
+	IssueShortLivedToken(ctx context.Context, roomName string, ttl time.Duration, isOwner bool) (string, time.Time, error)
+	GetMeetingToken(ctx context.Context, token string) (*GetMeetingTokenResponse, error)
+	GetMeetingTokens(ctx context.Context, tokens []string, concurrency int) (map[string]*GetMeetingTokenResponse, map[string]error)
+	InspectToken(ctx context.Context, token string) (*TokenInspection, error)
+}
+
+// RecordingService covers the recording methods of Client.
+type RecordingService interface {
+	GetRecordings(ctx context.Context, p GetRecordingsParams) (*GetRecordingResponse, error)
+	GetRecordingsByStatus(ctx context.Context, status RecordingStatus) ([]Recording, error)
+	ListRecordingsSince(ctx context.Context, since time.Time) ([]Recording, error)
+	AllRecordingsForRoom(ctx context.Context, roomName string) ([]Recording, error)
+	GetRecording(ctx context.Context, recordingID string) (*Recording, error)
+	StartRecording(ctx context.Context, name string, req *StartRecordingRequest) (*StartRecordingResponse, error)
+	StopRecording(ctx context.Context, name string) error
+	DeleteRecording(ctx context.Context, recordingID string) error
+	WaitForRecordingReady(ctx context.Context, recordingID string, pollInterval time.Duration) (*Recording, error)
+	GetRecordingLink(ctx context.Context, recordingID string) (*GetRecordingLinkResponse, error)
+	GetRecordingLinks(ctx context.Context, ids []string, concurrency int) (map[string]*GetRecordingLinkResponse, map[string]error)
+	GetRecordingLinkV2(ctx context.Context, recordingID string) (*RecordingLink, error)
+	GetRecordingLinkWithTTL(ctx context.Context, recordingID string, validFor time.Duration) (*GetRecordingLinkResponse, error)
+	WaitForRecordingLink(ctx context.Context, recordingID string, pollInterval time.Duration) (*GetRecordingLinkResponse, error)
+	GetRecordingTracks(ctx context.Context, recordingID string) ([]RecordingTrack, error)
+	GetTrackDownloadLink(ctx context.Context, recordingID, trackID string) (*GetRecordingLinkResponse, error)
+}
+
+// MeetingService covers the meeting session and usage methods of Client.
+type MeetingService interface {
+	GetMeetings(ctx context.Context, p GetMeetingsParams) (*GetMeetingsResponse, error)
+	GetMeetingSession(ctx context.Context, sessionID string) (*MeetingSession, error)
+	GetRoomUsage(ctx context.Context, roomName string, p UsageParams) (*UsageResponse, error)
+	GetParticipantMinutes(ctx context.Context, p GetMeetingsParams) (map[string]time.Duration, error)
+}
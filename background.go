@@ -0,0 +1,70 @@
+package daily
+
+import "context"
+
+// BackgroundClient is a convenience wrapper for scripts and tooling which
+// makes every call with context.Background(). Libraries and services should
+// use Client directly so calls can be canceled and given deadlines.
+type BackgroundClient struct {
+	c *Client
+}
+
+// Background returns a BackgroundClient for c.
+func (c *Client) Background() *BackgroundClient {
+	return &BackgroundClient{c: c}
+}
+
+// GetDomainConfig calls Client.GetDomainConfig with a background context.
+func (b *BackgroundClient) GetDomainConfig() (*DomainConfig, error) {
+	return b.c.GetDomainConfig(context.Background())
+}
+
+// SetDomainConfig calls Client.SetDomainConfig with a background context.
+func (b *BackgroundClient) SetDomainConfig(req *Config) (*DomainConfig, error) {
+	return b.c.SetDomainConfig(context.Background(), req)
+}
+
+// ListRooms calls Client.ListRooms with a background context.
+func (b *BackgroundClient) ListRooms(req *ListRoomsRequest) (*ListRoomsResponse, error) {
+	return b.c.ListRooms(context.Background(), req)
+}
+
+// CreateRoom calls Client.CreateRoom with a background context.
+func (b *BackgroundClient) CreateRoom(req *CreateRoomRequest) (*CreateRoomResponse, error) {
+	return b.c.CreateRoom(context.Background(), req)
+}
+
+// GetRoom calls Client.GetRoom with a background context.
+func (b *BackgroundClient) GetRoom(name string) (*GetRoomResponse, error) {
+	return b.c.GetRoom(context.Background(), name)
+}
+
+// UpdateRoom calls Client.UpdateRoom with a background context.
+func (b *BackgroundClient) UpdateRoom(name string, req *UpdateRoomRequest) (*UpdateRoomResponse, error) {
+	return b.c.UpdateRoom(context.Background(), name, req)
+}
+
+// DeleteRoom calls Client.DeleteRoom with a background context.
+func (b *BackgroundClient) DeleteRoom(name string) error {
+	return b.c.DeleteRoom(context.Background(), name)
+}
+
+// CreateMeetingToken calls Client.CreateMeetingToken with a background context.
+func (b *BackgroundClient) CreateMeetingToken(req *CreateMeetingTokenRequest) (*CreateMeetingTokenResponse, error) {
+	return b.c.CreateMeetingToken(context.Background(), req)
+}
+
+// GetMeetingToken calls Client.GetMeetingToken with a background context.
+func (b *BackgroundClient) GetMeetingToken(token string) (*GetMeetingTokenResponse, error) {
+	return b.c.GetMeetingToken(context.Background(), token)
+}
+
+// GetRecordings calls Client.GetRecordings with a background context.
+func (b *BackgroundClient) GetRecordings(p GetRecordingsParams) (*GetRecordingResponse, error) {
+	return b.c.GetRecordings(context.Background(), p)
+}
+
+// GetRecordingLink calls Client.GetRecordingLink with a background context.
+func (b *BackgroundClient) GetRecordingLink(recordingID string) (*GetRecordingLinkResponse, error) {
+	return b.c.GetRecordingLink(context.Background(), recordingID)
+}
@@ -0,0 +1,104 @@
+package daily
+
+import (
+	"context"
+	"sync"
+)
+
+// CreateMeetingTokens creates meeting tokens concurrently, using at most
+// concurrency requests at a time. Results and errors are in the same order as
+// reqs; for each index exactly one of them is set. Requests not yet started
+// when ctx is canceled fail with the context's error.
+func (c *Client) CreateMeetingTokens(ctx context.Context, reqs []*CreateMeetingTokenRequest, concurrency int) ([]*CreateMeetingTokenResponse, []error) {
+	resps := make([]*CreateMeetingTokenResponse, len(reqs))
+	errs := runBatch(ctx, len(reqs), concurrency, func(i int) error {
+		resp, err := c.CreateMeetingToken(ctx, reqs[i])
+		if err == nil {
+			resps[i] = resp
+		}
+		return err
+	})
+	return resps, errs
+}
+
+// GetMeetingTokens validates meeting tokens concurrently, using at most
+// concurrency requests at a time. Each token is in exactly one of the returned
+// maps. Tokens not yet validated when ctx is canceled fail with the context's
+// error.
+func (c *Client) GetMeetingTokens(ctx context.Context, tokens []string, concurrency int) (map[string]*GetMeetingTokenResponse, map[string]error) {
+	resps := make([]*GetMeetingTokenResponse, len(tokens))
+	errs := runBatch(ctx, len(tokens), concurrency, func(i int) error {
+		resp, err := c.GetMeetingToken(ctx, tokens[i])
+		if err == nil {
+			resps[i] = resp
+		}
+		return err
+	})
+
+	results := map[string]*GetMeetingTokenResponse{}
+	failures := map[string]error{}
+	for i, token := range tokens {
+		if errs[i] != nil {
+			failures[token] = errs[i]
+		} else {
+			results[token] = resps[i]
+		}
+	}
+	return results, failures
+}
+
+// GetRecordingLinks fetches download links for recordings concurrently, using
+// at most concurrency requests at a time. Each ID is in exactly one of the
+// returned maps. IDs not yet fetched when ctx is canceled fail with the
+// context's error.
+func (c *Client) GetRecordingLinks(ctx context.Context, ids []string, concurrency int) (map[string]*GetRecordingLinkResponse, map[string]error) {
+	resps := make([]*GetRecordingLinkResponse, len(ids))
+	errs := runBatch(ctx, len(ids), concurrency, func(i int) error {
+		resp, err := c.GetRecordingLink(ctx, ids[i])
+		if err == nil {
+			resps[i] = resp
+		}
+		return err
+	})
+
+	results := map[string]*GetRecordingLinkResponse{}
+	failures := map[string]error{}
+	for i, id := range ids {
+		if errs[i] != nil {
+			failures[id] = errs[i]
+		} else {
+			results[id] = resps[i]
+		}
+	}
+	return results, failures
+}
+
+// runBatch calls fn for every index in [0, n) from at most concurrency
+// goroutines and returns the errors by index.
+func runBatch(ctx context.Context, n, concurrency int, fn func(i int) error) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	errs := make([]error, n)
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+	return errs
+}
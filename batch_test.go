@@ -0,0 +1,99 @@
+package daily
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetMeetingTokens(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.URL.Path, "/v1/meeting-tokens/")
+		if strings.HasPrefix(token, "bad") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid-request-error","info":"token is invalid"}`))
+			return
+		}
+		w.Write([]byte(`{"room_name":"` + token + `-room"}`))
+	})
+
+	tokens := []string{"good1", "bad1", "good2", "bad2", "good3"}
+	results, failures := c.GetMeetingTokens(context.Background(), tokens, 2)
+
+	if len(results) != 3 || len(failures) != 2 {
+		t.Fatalf("got %d results and %d failures, want 3 and 2", len(results), len(failures))
+	}
+	for _, token := range []string{"good1", "good2", "good3"} {
+		resp := results[token]
+		if resp == nil || resp.RoomName == nil || *resp.RoomName != token+"-room" {
+			t.Errorf("%s: got %+v", token, resp)
+		}
+	}
+	for _, token := range []string{"bad1", "bad2"} {
+		if e, ok := failures[token].(Error); !ok || e.Message != ErrBadRequest {
+			t.Errorf("%s: err = %v, want ErrBadRequest", token, failures[token])
+		}
+	}
+}
+
+func TestGetMeetingTokensCanceled(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent with a canceled context")
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, failures := c.GetMeetingTokens(ctx, []string{"a", "b"}, 2)
+	if len(results) != 0 || len(failures) != 2 {
+		t.Fatalf("got %d results and %d failures, want 0 and 2", len(results), len(failures))
+	}
+	for token, err := range failures {
+		if err != context.Canceled {
+			t.Errorf("%s: err = %v, want context.Canceled", token, err)
+		}
+	}
+}
+
+func TestGetRecordingLinks(t *testing.T) {
+	var inFlight, maxInFlight int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/recordings/"), "/access-link")
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not-found"}`))
+			return
+		}
+		w.Write([]byte(`{"download_link":"https://example.com/` + id + `.mp4"}`))
+	})
+
+	ids := []string{"r1", "r2", "missing", "r4", "r5"}
+	links, failures := c.GetRecordingLinks(context.Background(), ids, 2)
+
+	if len(links) != 4 || len(failures) != 1 {
+		t.Fatalf("got %d links and %d failures, want 4 and 1", len(links), len(failures))
+	}
+	for _, id := range []string{"r1", "r2", "r4", "r5"} {
+		if want := "https://example.com/" + id + ".mp4"; links[id] == nil || links[id].DownloadLink != want {
+			t.Errorf("%s: got %+v, want %s", id, links[id], want)
+		}
+	}
+	if e, ok := failures["missing"].(Error); !ok || e.Message != ErrNotFound {
+		t.Errorf("missing: err = %v, want ErrNotFound", failures["missing"])
+	}
+	if maxInFlight > 2 {
+		t.Errorf("%d requests in flight at once, want at most 2", maxInFlight)
+	}
+}
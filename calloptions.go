@@ -0,0 +1,57 @@
+package daily
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// CallOption adjusts a single call. See WithCallOptions.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	timeout time.Duration
+	header  http.Header
+}
+
+type callOptionsCtx struct{}
+
+// WithCallOptions returns a context which applies opts to calls made with it,
+// in addition to any options already in ctx.
+//
+//	ctx = daily.WithCallOptions(ctx, daily.CallTimeout(time.Minute))
+//	room, err := client.GetRoom(ctx, name)
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	co := &callOptions{header: http.Header{}}
+	if prev, ok := ctx.Value(callOptionsCtx{}).(*callOptions); ok {
+		co.timeout = prev.timeout
+		for k, v := range prev.header {
+			co.header[k] = append([]string(nil), v...)
+		}
+	}
+	for _, opt := range opts {
+		opt(co)
+	}
+	return context.WithValue(ctx, callOptionsCtx{}, co)
+}
+
+// CallTimeout bounds the call to d, replacing the default timeout. A shorter
+// deadline already on the context still applies.
+func CallTimeout(d time.Duration) CallOption {
+	return func(co *callOptions) {
+		co.timeout = d
+	}
+}
+
+// CallHeader adds a header to the call. As with WithHeader, headers set by
+// the library itself take precedence.
+func CallHeader(key, value string) CallOption {
+	return func(co *callOptions) {
+		co.header.Add(key, value)
+	}
+}
+
+func callOptionsFrom(ctx context.Context) *callOptions {
+	co, _ := ctx.Value(callOptionsCtx{}).(*callOptions)
+	return co
+}
@@ -0,0 +1,46 @@
+package daily
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCallTimeout(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{"name":"room"}`))
+	})
+
+	ctx := WithCallOptions(context.Background(), CallTimeout(20*time.Millisecond))
+	if _, err := c.GetRoom(ctx, "room"); err == nil {
+		t.Error("GetRoom outlived its CallTimeout")
+	}
+
+	ctx = WithCallOptions(context.Background(), CallTimeout(5*time.Second))
+	if _, err := c.GetRoom(ctx, "room"); err != nil {
+		t.Errorf("GetRoom with a generous CallTimeout: %v", err)
+	}
+}
+
+func TestCallHeader(t *testing.T) {
+	var got http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		w.Write([]byte(`{"name":"room"}`))
+	})
+
+	ctx := WithCallOptions(context.Background(), CallHeader("X-Tenant", "acme"))
+	ctx = WithCallOptions(ctx, CallHeader("X-Trace", "a"), CallHeader("X-Trace", "b"))
+	if _, err := c.GetRoom(ctx, "room"); err != nil {
+		t.Fatalf("GetRoom: %v", err)
+	}
+	if v := got.Get("X-Tenant"); v != "acme" {
+		t.Errorf("X-Tenant = %q, want the header from the outer options", v)
+	}
+	if v := got["X-Trace"]; !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("X-Trace = %q, want [a b]", v)
+	}
+}
@@ -11,6 +11,10 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,6 +22,15 @@ const (
 	libraryVersion = "0.1"
 	userAgent      = "daily-go/" + libraryVersion
 	defaultBaseURL = "https://api.daily.co/v1/"
+
+	defaultMaxResponseBytes = 10 << 20
+
+	// maxPageSize is the largest page Daily's list endpoints return. Helpers
+	// which page through everything use it as their page size.
+	maxPageSize = 100
+
+	// maxRawDetails is how much of a non-JSON error body Error.RawDetails keeps.
+	maxRawDetails = 512
 )
 
 // Option defines an option for a client.
@@ -26,27 +39,149 @@ type Option func(*Client)
 // WithAuth wraps the http client with necessary authentication headers.
 func WithAuth(accessToken string) Option {
 	return func(c *Client) {
-		c.HTTPClient = &authClient{
-			httpClient:  c.HTTPClient,
-			accessToken: accessToken,
+		c.layers[authLayer] = func(hc httpClient) httpClient {
+			return &authClient{httpClient: hc, accessToken: accessToken}
 		}
 	}
 }
 
+// WithTransport sets the round tripper used by the underlying http.Client,
+// e.g. a tuned *http.Transport for high request volumes. Other http.Client
+// settings are preserved.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.HTTPClient = withTransport(c.HTTPClient, rt)
+	}
+}
+
+// WithAPIVersion sets the version segment of the base URL, e.g. "v1" in
+// https://api.daily.co/v1/. It replaces the last path segment of the current
+// base URL, so it also works with a custom base URL.
+func WithAPIVersion(v string) Option {
+	return func(c *Client) {
+		c.BaseURL = *c.BaseURL.ResolveReference(&url.URL{Path: "../" + v + "/"})
+	}
+}
+
+// WithStrictDecoding makes responses containing fields unknown to this library
+// fail to decode, which helps catch API drift early. By default unknown fields
+// are ignored. Such responses fail with ErrUnknownField, with the quoted name
+// of the field in RawDetails.
+func WithStrictDecoding() Option {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey returns a context which sends key in the Idempotency-Key
+// header of any request made with it. Reuse the same key when retrying a
+// CreateRoom or CreateMeetingToken call that may already have succeeded.
+//
+// Daily doesn't document support for this header, in which case it is passed
+// through unchanged and has no effect. For that reason WithRetries doesn't
+// retry a POST on a server error even when it carries a key.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// WithMaxResponseBytes limits how much of a response body is read. Larger
+// responses fail with ErrResponseTooLarge. The default, also used when n isn't
+// positive, is 10 MB.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		if n <= 0 {
+			n = defaultMaxResponseBytes
+		}
+		c.maxResponseBytes = n
+	}
+}
+
+// WithDryRun builds requests without sending them. Each request is passed to
+// fn and the call fails with ErrDryRun; no network call happens. The
+// Authorization header is added when sending, so it isn't included.
+func WithDryRun(fn func(*http.Request)) Option {
+	return func(c *Client) {
+		c.dryRun = fn
+	}
+}
+
+// WithHeader adds a header sent with every request, e.g. for a gateway in front
+// of Daily. It can be used multiple times. Headers set by the library itself,
+// such as User-Agent and Authorization, take precedence.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		c.headers.Add(key, value)
+	}
+}
+
+// WithCorrelationIDFunc sends the ID returned by fn for each call's context in
+// the X-Correlation-ID header, tying Daily calls to the request that caused
+// them. Empty IDs aren't sent.
+func WithCorrelationIDFunc(fn func(context.Context) string) Option {
+	return func(c *Client) {
+		c.correlationID = fn
+	}
+}
+
+// WithDefaultRequestTimeout bounds every request to d, in addition to any
+// deadline on the caller's context or set with CallTimeout; whichever is
+// sooner applies. Without it, requests whose context has no deadline time out
+// after 5 seconds, and those with one are bounded only by it.
+func WithDefaultRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = d
+	}
+}
+
+// WithClock sets the function the client reads the current time from, e.g. a
+// fixed clock in tests. It is used for token and room expiry calculations and
+// cache TTLs, but not for timeouts, retry backoffs or Probe latencies, which
+// follow real time.
+// The default is time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
 // Client for the daily.co API.
 type Client struct {
 	HTTPClient httpClient
 	BaseURL    url.URL
 	UserAgent  string
+
+	strictDecoding   bool
+	tokenCache       *tokenCache
+	maxResponseBytes int64
+	domainCache      *domainConfigCache
+	dryRun           func(*http.Request)
+	headers          http.Header
+	correlationID    func(context.Context) string
+	now              func() time.Time
+	requestTimeout   time.Duration
+	layers           [numLayers]middleware // Wrap HTTPClient when sending
+	retry            *retryConfig
 }
 
 // New builds a new Daily client.
+//
+// Requests are bounded by their context's deadline. Calls made with a context
+// that has no deadline time out after 5 seconds.
 func New(opts ...Option) *Client {
 	baseURL, _ := url.Parse(defaultBaseURL)
 	c := &Client{
-		HTTPClient: &http.Client{Timeout: time.Second * 5},
+		HTTPClient: &http.Client{},
 		BaseURL:    *baseURL,
 		UserAgent:  userAgent,
+
+		maxResponseBytes: defaultMaxResponseBytes,
+		domainCache:      newDomainConfigCache(),
+		now:              time.Now,
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -54,18 +189,92 @@ func New(opts ...Option) *Client {
 	return c
 }
 
+// Close stops the client's background goroutines, such as the token cache's
+// janitor, and closes idle keep-alive connections if HTTPClient supports it,
+// as http.Client does. The client can still be used afterwards, and Close can
+// be called more than once.
+func (c *Client) Close() error {
+	if c.tokenCache != nil {
+		c.tokenCache.stop()
+	}
+	if hc, ok := c.HTTPClient.(interface{ CloseIdleConnections() }); ok {
+		hc.CloseIdleConnections()
+	}
+	return nil
+}
+
+// WithToken returns a copy of the client which authenticates with accessToken
+// instead, e.g. to act on another Daily domain. The copy shares the original's
+// HTTPClient and settings, but has its own domain config cache and no token
+// cache. The original is unchanged; closing it closes the copy's idle
+// connections too.
+func (c *Client) WithToken(accessToken string) *Client {
+	cp := *c
+	WithAuth(accessToken)(&cp)
+	cp.domainCache = newDomainConfigCache()
+	cp.domainCache.ttl = c.domainCache.ttl
+	cp.tokenCache = nil
+	return &cp
+}
+
+// VerifyCredentials checks the access token works, e.g. for a startup health
+// check, by fetching the domain config. A missing or malformed token fails
+// with ErrUnauthorized and a token lacking access with ErrForbidden.
+func (c *Client) VerifyCredentials(ctx context.Context) error {
+	_, err := c.GetDomainConfig(ctx)
+	return err
+}
+
+// Probe makes a lightweight authenticated request, listing a single room, for
+// readiness checks and dependency dashboards. The result is filled in as far
+// as possible even when an error is returned, whose Error.Message classifies
+// the failure.
+func (c *Client) Probe(ctx context.Context) (ProbeResult, error) {
+	cl := &call{}
+	start := time.Now()
+	err := c.requestCall(ctx, cl, "GET", withQuery("rooms", queryValues(ListRoomsRequest{Limit: 1})), nil, &ListRoomsResponse{})
+	res := ProbeResult{StatusCode: cl.status, Latency: time.Since(start)}
+	if cl.respHeader != nil {
+		limit, limitErr := strconv.Atoi(cl.respHeader.Get("X-RateLimit-Limit"))
+		remaining, remainingErr := strconv.Atoi(cl.respHeader.Get("X-RateLimit-Remaining"))
+		if limitErr == nil && remainingErr == nil {
+			res.RateLimit = &RateLimit{Limit: limit, Remaining: remaining}
+		}
+	}
+	return res, err
+}
+
 // GetDomainConfig returns domain configuration information
 func (c *Client) GetDomainConfig(ctx context.Context) (*DomainConfig, error) {
 	resp := &DomainConfig{}
 	return resp, c.request(ctx, "GET", "", nil, resp)
 }
 
-// SetDomainConfig updates domain configuration information.
+// SetDomainConfig updates domain configuration information and returns the
+// complete, effective domain configuration afterwards, not just the fields
+// sent. Daily may echo back only the fields that changed, so the
+// configuration is always fetched again after the update.
 func (c *Client) SetDomainConfig(ctx context.Context, req *Config) (*DomainConfig, error) {
-	resp := &DomainConfig{}
-	return resp, c.request(ctx, "POST", "", struct {
-		Properties *Config
-	}{req}, resp)
+	c.domainCache.invalidate()
+	err := c.request(ctx, "POST", "", struct {
+		Properties *Config `json:"properties"`
+	}{req}, nil)
+	if err != nil {
+		return &DomainConfig{}, err
+	}
+	return c.RefreshDomainConfig(ctx)
+}
+
+// GetRoomConfigDefaults returns the room configuration implied by the domain
+// configuration. Only settings shared between domains and rooms can be
+// derived, see Config.RoomDefaults; domain-only settings such as branding
+// and HIPAA mode are left out.
+func (c *Client) GetRoomConfigDefaults(ctx context.Context) (*RoomConfig, error) {
+	domain, err := c.GetDomainConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Config.RoomDefaults(), nil
 }
 
 // ListRooms returns available rooms.
@@ -73,71 +282,639 @@ func (c *Client) ListRooms(ctx context.Context, req *ListRoomsRequest) (*ListRoo
 	if req == nil {
 		req = &ListRoomsRequest{}
 	}
+	p := *req
+	if p.Limit < 0 {
+		p.Limit = 0
+	}
+	if p.StartingAfter == "" {
+		p.StartingAfter = p.EndingAfter
+	}
 	resp := &ListRoomsResponse{}
-	return resp, c.request(ctx, "GET", "rooms", req, resp)
+	return resp, c.request(ctx, "GET", withQuery("rooms", queryValues(p)), nil, resp)
+}
+
+// ListRoomsMatching pages through every room and returns those for which
+// predicate returns true. Daily can't filter rooms server-side.
+func (c *Client) ListRoomsMatching(ctx context.Context, predicate func(Room) bool) ([]Room, error) {
+	req := &ListRoomsRequest{Limit: maxPageSize}
+	var rooms []Room
+	for {
+		page, err := c.ListRooms(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, room := range page.Rooms {
+			if predicate(room) {
+				rooms = append(rooms, room)
+			}
+		}
+		if len(page.Rooms) < int(req.Limit) {
+			return rooms, nil
+		}
+		req.StartingAfter = page.NextCursor()
+	}
 }
 
 // CreateRoom creats a new room.
 func (c *Client) CreateRoom(ctx context.Context, req *CreateRoomRequest) (*CreateRoomResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
 	resp := &CreateRoomResponse{}
 	return resp, c.request(ctx, "POST", "rooms", req, resp)
 }
 
-// GetRoom returns a single room object.
+// CreateTemporaryRoom creates a room which expires ttl from now, per the
+// client's clock, ejecting participants when it does. req may be nil; its Name
+// and expiry settings are overridden.
+func (c *Client) CreateTemporaryRoom(ctx context.Context, name string, ttl time.Duration, req *CreateRoomRequest) (*CreateRoomResponse, error) {
+	create := CreateRoomRequest{}
+	if req != nil {
+		create = *req
+	}
+	cfg := RoomConfig{}
+	if create.Config != nil {
+		cfg = *create.Config
+	}
+	cfg.ExpiresAt = Timestamp(c.now().Add(ttl))
+	cfg.EjectAtRoomExpiry = True()
+	create.Name = String(name)
+	create.Config = &cfg
+	return c.CreateRoom(ctx, &create)
+}
+
+// CreateRoomFromTemplate creates a room with the template's privacy and config,
+// changed by each of overrides in turn. The template itself is not modified.
+func (c *Client) CreateRoomFromTemplate(ctx context.Context, t *RoomTemplate, overrides ...RoomOption) (*CreateRoomResponse, error) {
+	req, err := t.request()
+	if err != nil {
+		return nil, Error{Message: ErrParseError + ": " + err.Error()}
+	}
+	for _, o := range overrides {
+		o(req)
+	}
+	return c.CreateRoom(ctx, req)
+}
+
+// GetOrCreateRoom creates a room with the given name, or returns the existing
+// room if the name is already taken. req may be nil; its Name is ignored.
+//
+// Creating first, then falling back to fetching the room, means two callers
+// racing to create the same room both end up with the same room.
+func (c *Client) GetOrCreateRoom(ctx context.Context, name string, req *CreateRoomRequest) (*Room, error) {
+	create := CreateRoomRequest{}
+	if req != nil {
+		create = *req
+	}
+	create.Name = String(name)
+
+	created, err := c.CreateRoom(ctx, &create)
+	if err == nil {
+		return &created.Room, nil
+	}
+	if !isRoomExists(err) {
+		return nil, err
+	}
+	existing, err := c.GetRoom(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &existing.Room, nil
+}
+
+// GetRoom returns a single room object. Like every method taking a room name,
+// it expects Room.Name rather than Room.ID; see GetRoomByID.
 func (c *Client) GetRoom(ctx context.Context, name string) (*GetRoomResponse, error) {
 	resp := &GetRoomResponse{}
 	return resp, c.request(ctx, "GET", "rooms/"+name, nil, resp)
 }
 
+// GetRoomByID returns the room whose Room.ID is id, as given by webhooks.
+// Daily only looks rooms up by name, so this pages through every room; cache
+// the ID's name if calling it often. Fails with ErrNotFound if there is no
+// such room.
+func (c *Client) GetRoomByID(ctx context.Context, id string) (*Room, error) {
+	rooms, err := c.ListRoomsMatching(ctx, func(r Room) bool {
+		return r.ID == id
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rooms) == 0 {
+		return nil, Error{Message: ErrNotFound, StatusCode: http.StatusNotFound, RawDetails: "no room with id " + id}
+	}
+	return &rooms[0], nil
+}
+
+// GetRoomConditional fetches a room unless it is unchanged since the response
+// which returned etag. An empty etag always fetches the room. When the room is
+// unchanged, notModified is true and room is nil, so the caller's copy can be
+// reused.
+//
+// This only saves work if Daily sends ETags for rooms. If it doesn't, the
+// returned etag is empty and every call fetches the room.
+func (c *Client) GetRoomConditional(ctx context.Context, name, etag string) (room *GetRoomResponse, newETag string, notModified bool, err error) {
+	cl := &call{header: http.Header{}}
+	if etag != "" {
+		cl.header.Set("If-None-Match", etag)
+	}
+	resp := &GetRoomResponse{}
+	if err := c.requestCall(ctx, cl, "GET", "rooms/"+name, nil, resp); err != nil {
+		return nil, "", false, err
+	}
+	if cl.status == http.StatusNotModified {
+		return nil, etag, true, nil
+	}
+	return resp, cl.respHeader.Get("ETag"), false, nil
+}
+
 // UpdateRoom updates details about a room.
 func (c *Client) UpdateRoom(ctx context.Context, name string, req *UpdateRoomRequest) (*UpdateRoomResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
 	resp := &UpdateRoomResponse{}
 	return resp, c.request(ctx, "POST", "rooms/"+name, req, resp)
 }
 
+// GetRoomPresence returns the participants currently in a room.
+func (c *Client) GetRoomPresence(ctx context.Context, name string) (*GetRoomPresenceResponse, error) {
+	resp := &GetRoomPresenceResponse{}
+	return resp, c.request(ctx, "GET", "rooms/"+name+"/presence", nil, resp)
+}
+
+// GetRoomWithPresence fetches a room and its current participants concurrently.
+// If either lookup fails the other is canceled and the first error is returned.
+func (c *Client) GetRoomWithPresence(ctx context.Context, name string) (*Room, []PresenceParticipant, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		room     *GetRoomResponse
+		presence *GetRoomPresenceResponse
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		var err error
+		if room, err = c.GetRoom(ctx, name); err != nil {
+			fail(err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		if presence, err = c.GetRoomPresence(ctx, name); err != nil {
+			fail(err)
+		}
+	}()
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	return &room.Room, presence.Participants, nil
+}
+
+// PatchRoom updates a room's config by fetching the current config, applying
+// mutate to it and sending the result, so fields not touched by mutate keep
+// their current values.
+//
+// This is a read-modify-write: changes made by others between the fetch and
+// the update are overwritten. Use PatchRoomIf to guard against that.
+func (c *Client) PatchRoom(ctx context.Context, name string, mutate func(*RoomConfig)) (*UpdateRoomResponse, error) {
+	return c.PatchRoomIf(ctx, name, nil, mutate)
+}
+
+// PatchRoomIf is like PatchRoom, but only updates the room if precondition
+// returns true for the fetched room. Otherwise an ErrPreconditionFailed error
+// is returned. A nil precondition always passes.
+func (c *Client) PatchRoomIf(ctx context.Context, name string, precondition func(*Room) bool, mutate func(*RoomConfig)) (*UpdateRoomResponse, error) {
+	current, err := c.GetRoom(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if precondition != nil && !precondition(&current.Room) {
+		return nil, Error{Message: ErrPreconditionFailed}
+	}
+	cfg := current.Config
+	if cfg == nil {
+		cfg = &RoomConfig{}
+	}
+	mutate(cfg)
+	return c.UpdateRoom(ctx, name, &UpdateRoomRequest{Config: cfg})
+}
+
+// RecreateRoom "renames" a room by creating newName with the privacy and config
+// of oldName and then deleting oldName, since Daily can't rename rooms. The
+// new room gets a new ID and URL. If deleting oldName fails, both rooms exist
+// and the error is returned alongside the new room.
+func (c *Client) RecreateRoom(ctx context.Context, oldName, newName string) (*Room, error) {
+	old, err := c.GetRoom(ctx, oldName)
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.CreateRoom(ctx, &CreateRoomRequest{
+		Name:    String(newName),
+		Privacy: old.Privacy,
+		Config:  old.Config,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created.Room, c.DeleteRoom(ctx, oldName)
+}
+
+// SendAppMessage sends msg to participants currently in a room, who receive it
+// as an app-message event. With no recipients the message is broadcast to
+// everyone, otherwise it is sent to each participant session ID in turn,
+// stopping at the first failure.
+func (c *Client) SendAppMessage(ctx context.Context, roomName string, msg json.RawMessage, recipients []string) error {
+	if len(recipients) == 0 {
+		recipients = []string{"*"}
+	}
+	for _, r := range recipients {
+		req := &SendAppMessageRequest{Data: msg, Recipient: r}
+		if err := c.request(ctx, "POST", "rooms/"+roomName+"/send-app-message", req, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteRoom deletes a room.
 func (c *Client) DeleteRoom(ctx context.Context, name string) error {
 	// Throw away response. It has a 'deleted' property which is always true.
-	resp := map[string]interface{}{}
-	return c.request(ctx, "DELETE", "rooms/"+name, nil, &resp)
+	return c.request(ctx, "DELETE", "rooms/"+name, nil, nil)
 }
 
 // CreateMeetingToken creates a meeting token.
+// A response without a token fails with ErrEmptyToken, so Token is always set
+// when err is nil.
 func (c *Client) CreateMeetingToken(ctx context.Context, req *CreateMeetingTokenRequest) (*CreateMeetingTokenResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
 	resp := &CreateMeetingTokenResponse{}
-	return resp, c.request(ctx, "POST", "meeting-tokens", req, resp)
+	if err := c.request(ctx, "POST", "meeting-tokens", req, resp); err != nil {
+		return resp, err
+	}
+	if resp.Token == nil || *resp.Token == "" {
+		return nil, Error{Message: ErrEmptyToken, StatusCode: http.StatusOK}
+	}
+	return resp, nil
+}
+
+// CreateJoinURL creates a meeting token for roomName and returns the room's URL
+// with the token attached, ready to join. token may be nil; its RoomName is
+// set to roomName.
+func (c *Client) CreateJoinURL(ctx context.Context, roomName string, token *MeetingToken) (string, error) {
+	room, err := c.GetRoom(ctx, roomName)
+	if err != nil {
+		return "", err
+	}
+	props := MeetingToken{}
+	if token != nil {
+		props = *token
+	}
+	props.RoomName = String(roomName)
+	resp, err := c.CreateMeetingToken(ctx, &CreateMeetingTokenRequest{Properties: &props})
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(room.URL)
+	if err != nil {
+		return "", Error{Message: ErrParseError + ": " + err.Error(), RawDetails: room.URL}
+	}
+	q := u.Query()
+	q.Set("t", *resp.Token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// IssueShortLivedToken creates a meeting token for roomName which is valid from
+// now for ttl, returning the token and its expiry so callers can schedule a
+// refresh before it lapses.
+func (c *Client) IssueShortLivedToken(ctx context.Context, roomName string, ttl time.Duration, isOwner bool) (string, time.Time, error) {
+	now := c.now()
+	exp := Timestamp(now.Add(ttl))
+	resp, err := c.CreateMeetingToken(ctx, &CreateMeetingTokenRequest{
+		Properties: &MeetingToken{
+			RoomName:  String(roomName),
+			IsOwner:   Bool(isOwner),
+			NotBefore: Timestamp(now),
+			ExpiresAt: exp,
+		},
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return *resp.Token, time.Unix(*exp, 0), nil
 }
 
 // GetMeetingToken validates and returns the properties of a meeting token.
 func (c *Client) GetMeetingToken(ctx context.Context, token string) (*GetMeetingTokenResponse, error) {
+	if c.tokenCache != nil {
+		if resp, ok := c.tokenCache.get(token, c.now()); ok {
+			return resp, nil
+		}
+	}
 	resp := &GetMeetingTokenResponse{}
-	return resp, c.request(ctx, "GET", "meeting-tokens/"+token, nil, resp)
+	if err := c.request(ctx, "GET", "meeting-tokens/"+token, nil, resp); err != nil {
+		return resp, err
+	}
+	if c.tokenCache != nil {
+		c.tokenCache.add(token, resp, c.now())
+	}
+	return resp, nil
 }
 
+// InspectToken fetches a meeting token and the room it references and reports
+// inconsistencies which would stop its holder from joining, such as the room
+// no longer existing or expiring before the token does.
+func (c *Client) InspectToken(ctx context.Context, token string) (*TokenInspection, error) {
+	props, err := c.GetMeetingToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	in := &TokenInspection{Token: props}
+	now := c.now()
+	if props.ExpiresAt != nil && !now.Before(time.Unix(*props.ExpiresAt, 0)) {
+		in.Issues = append(in.Issues, "token has expired")
+	}
+	if props.RoomName == nil {
+		in.Issues = append(in.Issues, "token isn't restricted to a room")
+		return in, nil
+	}
+
+	room, err := c.GetRoom(ctx, *props.RoomName)
+	if e, ok := err.(Error); ok && e.Message == ErrNotFound {
+		in.Issues = append(in.Issues, fmt.Sprintf("room %s doesn't exist", *props.RoomName))
+		return in, nil
+	} else if err != nil {
+		return nil, err
+	}
+	in.Room = &room.Room
+
+	if in.Room.IsExpired(now) {
+		in.Issues = append(in.Issues, "room has expired")
+	}
+	if cfg := in.Room.Config; cfg != nil {
+		if cfg.ExpiresAt != nil && props.ExpiresAt != nil && *props.ExpiresAt > *cfg.ExpiresAt {
+			in.Issues = append(in.Issues, "token expires after the room")
+		}
+		if cfg.ExpiresAt != nil && props.NotBefore != nil && *props.NotBefore >= *cfg.ExpiresAt {
+			in.Issues = append(in.Issues, "token only becomes valid after the room expires")
+		}
+	}
+	return in, nil
+}
+
+// GetRecordingsParams filters and pages the recordings returned by
+// GetRecordings. Each field is sent as the query parameter in its url tag.
 type GetRecordingsParams struct {
-	Limit         int    `json:"limit"`
-	EndingBefore  string `json:"ending_before"`
-	StartingAfter string `json:"starting_after"`
-	RoomName      string `json:"room_name"`
+	Limit         int    `url:"limit,omitempty"`
+	EndingBefore  string `url:"ending_before,omitempty"`
+	StartingAfter string `url:"starting_after,omitempty"`
+	RoomName      string `url:"room_name,omitempty"`
 }
 
+// GetRecordings returns a page of recordings, newest first. A Limit above
+// Daily's maximum page size of 100 is lowered to it.
+//
+// A negative Limit fetches every matching recording instead, paging through
+// them internally, and returns them all in one response.
 func (c *Client) GetRecordings(ctx context.Context, p GetRecordingsParams) (*GetRecordingResponse, error) {
+	if p.Limit < 0 {
+		p.Limit = 0
+		all := &GetRecordingResponse{}
+		err := c.eachRecording(ctx, p, func(r Recording) bool {
+			all.Recording = append(all.Recording, r)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		all.TotalCount = len(all.Recording)
+		return all, nil
+	}
+	if p.Limit > maxPageSize {
+		p.Limit = maxPageSize
+	}
 	resp := &GetRecordingResponse{}
-	path := "/v1/recordings"
-	var params []string
-	if p.Limit > 0 {
-		params = append(params, fmt.Sprintf("limit=%d", p.Limit))
+	return resp, c.request(ctx, "GET", withQuery("recordings", queryValues(p)), nil, resp)
+}
+
+// GetMeetingsParams filters the meeting sessions returned by GetMeetings.
+type GetMeetingsParams struct {
+	Room           string `url:"room,omitempty"`
+	TimeframeStart int64  `url:"timeframe_start,omitempty"` // Unix timestamp in seconds
+	TimeframeEnd   int64  `url:"timeframe_end,omitempty"`   // Unix timestamp in seconds
+	Limit          int    `url:"limit,omitempty"`
+	StartingAfter  string `url:"starting_after,omitempty"`
+	EndingBefore   string `url:"ending_before,omitempty"`
+}
+
+// GetMeetings returns a page of meeting sessions. Limit is capped at Daily's
+// maximum page size of 100.
+func (c *Client) GetMeetings(ctx context.Context, p GetMeetingsParams) (*GetMeetingsResponse, error) {
+	if p.Limit > maxPageSize {
+		p.Limit = maxPageSize
 	}
-	if p.EndingBefore != "" {
-		params = append(params, fmt.Sprintf("&ending_before=%s", p.EndingBefore))
+	resp := &GetMeetingsResponse{}
+	return resp, c.request(ctx, "GET", withQuery("meetings", queryValues(p)), nil, resp)
+}
+
+// GetMeetingSession returns a single meeting session and its participants.
+func (c *Client) GetMeetingSession(ctx context.Context, sessionID string) (*MeetingSession, error) {
+	resp := &MeetingSession{}
+	return resp, c.request(ctx, "GET", "meetings/"+sessionID, nil, resp)
+}
+
+// UsageParams sets the timeframe for GetRoomUsage. Zero times are unbounded.
+type UsageParams struct {
+	Start time.Time
+	End   time.Time
+}
+
+// GetRoomUsage totals the session and participant minutes of a room's
+// meetings within the timeframe, paging through every matching session.
+func (c *Client) GetRoomUsage(ctx context.Context, roomName string, p UsageParams) (*UsageResponse, error) {
+	params := GetMeetingsParams{Room: roomName}
+	if !p.Start.IsZero() {
+		params.TimeframeStart = p.Start.Unix()
 	}
-	if p.StartingAfter != "" {
-		params = append(params, fmt.Sprintf("&starting_after=%s", p.StartingAfter))
+	if !p.End.IsZero() {
+		params.TimeframeEnd = p.End.Unix()
 	}
-	if p.RoomName != "" {
-		params = append(params, fmt.Sprintf("room_name=%s", p.RoomName))
+
+	usage := &UsageResponse{}
+	err := c.eachMeeting(ctx, params, func(m MeetingSession) {
+		usage.Sessions++
+		usage.TotalMinutes += float64(m.Duration) / 60
+		for _, participant := range m.Participants {
+			usage.ParticipantMinutes += float64(participant.Duration) / 60
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// GetParticipantMinutes totals each user's time in calls across the meeting
+// sessions matching p, keyed by user ID, or participant ID for participants
+// without one. Overlapping attendance by the same user, e.g. from two tabs,
+// is only counted once.
+func (c *Client) GetParticipantMinutes(ctx context.Context, p GetMeetingsParams) (map[string]time.Duration, error) {
+	type interval struct{ start, end int64 }
+	attendance := map[string][]interval{}
+	err := c.eachMeeting(ctx, p, func(m MeetingSession) {
+		for _, participant := range m.Participants {
+			key := participant.UserID
+			if key == "" {
+				key = participant.ParticipantID
+			}
+			attendance[key] = append(attendance[key], interval{
+				start: participant.JoinTime,
+				end:   participant.JoinTime + int64(participant.Duration),
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]time.Duration, len(attendance))
+	for key, intervals := range attendance {
+		sort.Slice(intervals, func(i, j int) bool { return intervals[i].start < intervals[j].start })
+		var total, end int64
+		for i, in := range intervals {
+			switch {
+			case i == 0 || in.start >= end:
+				total += in.end - in.start
+				end = in.end
+			case in.end > end:
+				total += in.end - end
+				end = in.end
+			}
+		}
+		totals[key] = time.Duration(total) * time.Second
+	}
+	return totals, nil
+}
+
+// eachMeeting pages through the meeting sessions matching p, calling fn for
+// each.
+func (c *Client) eachMeeting(ctx context.Context, p GetMeetingsParams, fn func(MeetingSession)) error {
+	if p.Limit <= 0 || p.Limit > maxPageSize {
+		p.Limit = maxPageSize
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := c.GetMeetings(ctx, p)
+		if err != nil {
+			return err
+		}
+		for _, m := range page.Meetings {
+			fn(m)
+		}
+		if len(page.Meetings) < p.Limit {
+			return nil
+		}
+		p.StartingAfter = page.Meetings[len(page.Meetings)-1].ID
+	}
+}
+
+// GetRecordingsByStatus returns every recording with the given status. Daily
+// can't filter by status, so all recordings are paged through.
+func (c *Client) GetRecordingsByStatus(ctx context.Context, status RecordingStatus) ([]Recording, error) {
+	var recordings []Recording
+	err := c.eachRecording(ctx, GetRecordingsParams{}, func(r Recording) bool {
+		if r.Status == status {
+			recordings = append(recordings, r)
+		}
+		return true
+	})
+	return recordings, err
+}
+
+// AllRecordingsForRoom returns every recording of a room, newest first.
+func (c *Client) AllRecordingsForRoom(ctx context.Context, roomName string) ([]Recording, error) {
+	var recordings []Recording
+	err := c.eachRecording(ctx, GetRecordingsParams{RoomName: roomName}, func(r Recording) bool {
+		recordings = append(recordings, r)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(recordings, func(i, j int) bool {
+		return recordings[i].StartTs > recordings[j].StartTs
+	})
+	return recordings, nil
+}
+
+// ListRecordingsSince returns the domain's recordings started at or after
+// since, newest first. Paging stops at the first older recording, relying on
+// Daily listing recordings newest first.
+func (c *Client) ListRecordingsSince(ctx context.Context, since time.Time) ([]Recording, error) {
+	var recordings []Recording
+	err := c.eachRecording(ctx, GetRecordingsParams{}, func(r Recording) bool {
+		if int64(r.StartTs) < since.Unix() {
+			return false
+		}
+		recordings = append(recordings, r)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recordings, nil
+}
+
+// eachRecording pages through the recordings matching p, newest first, calling
+// fn for each until it returns false.
+func (c *Client) eachRecording(ctx context.Context, p GetRecordingsParams, fn func(Recording) bool) error {
+	if p.Limit <= 0 || p.Limit > maxPageSize {
+		p.Limit = maxPageSize
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := c.GetRecordings(ctx, p)
+		if err != nil {
+			return err
+		}
+		for _, r := range page.Recording {
+			if !fn(r) {
+				return nil
+			}
+		}
+		if len(page.Recording) < p.Limit {
+			return nil
+		}
+		p.StartingAfter = page.Recording[len(page.Recording)-1].Id
 	}
-	return resp, c.request(ctx, "GET", generateUrlWithQueryParams(path, params), nil, resp)
+}
+
+// GetRecording returns a single recording.
+func (c *Client) GetRecording(ctx context.Context, recordingID string) (*Recording, error) {
+	resp := &Recording{}
+	return resp, c.request(ctx, "GET", "recordings/"+recordingID, nil, resp)
 }
 
 // StartRecording starts a recording for a given room.
@@ -148,32 +925,146 @@ func (c *Client) StartRecording(ctx context.Context, name string, req *StartReco
 
 // StopRecording stops a recording for a given room.
 func (c *Client) StopRecording(ctx context.Context, name string) error {
-	resp := map[string]interface{}{}
-	return c.request(ctx, "POST", "rooms/"+name+"/recordings/stop", nil, &resp)
+	return c.request(ctx, "POST", "rooms/"+name+"/recordings/stop", nil, nil)
 }
 
 // DeleteRecording deletes a recording on Daily's side
 func (c *Client) DeleteRecording(ctx context.Context, recordingID string) error {
-	resp := map[string]interface{}{}
-	return c.request(ctx, "DELETE", "recordings/"+recordingID, nil, &resp)
+	return c.request(ctx, "DELETE", "recordings/"+recordingID, nil, nil)
 }
 
+// GetRecordingLink returns a download link for a recording. Recordings which
+// are still being processed fail with ErrRecordingNotReady; see
+// WaitForRecordingReady and WaitForRecordingLink.
 func (c *Client) GetRecordingLink(ctx context.Context, recordingID string) (*GetRecordingLinkResponse, error) {
 	resp := &GetRecordingLinkResponse{}
-	return resp, c.request(ctx, "GET", "recordings/"+recordingID+"/access-link", nil, resp)
+	err := c.request(ctx, "GET", "recordings/"+recordingID+"/access-link", nil, resp)
+	if e, ok := err.(Error); ok && isRecordingNotReady(e) {
+		e.Message = ErrRecordingNotReady
+		return resp, e
+	}
+	return resp, err
 }
 
-func generateUrlWithQueryParams(path string, params []string) string {
-	if len(params) > 0 {
-		path = path + "?" + params[0]
-		for _, param := range params[1:] {
-			path = path + "&" + param
+// WaitForRecordingReady polls GetRecording every pollInterval until the
+// recording is finished or canceled, giving up when ctx is done. Once it
+// returns a finished recording, GetRecordingLink can be called.
+func (c *Client) WaitForRecordingReady(ctx context.Context, recordingID string, pollInterval time.Duration) (*Recording, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		recording, err := c.GetRecording(ctx, recordingID)
+		if err != nil || recording.Status.IsTerminal() {
+			return recording, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("daily: recording %s not ready: %w", recordingID, ctx.Err())
+		case <-ticker.C:
 		}
 	}
-	return path
+}
+
+// GetRecordingTracks returns the tracks of a raw-tracks recording.
+func (c *Client) GetRecordingTracks(ctx context.Context, recordingID string) ([]RecordingTrack, error) {
+	recording, err := c.GetRecording(ctx, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	return recording.Tracks, nil
+}
+
+// GetTrackDownloadLink returns a download link for a single track of a
+// raw-tracks recording. trackID is the track's S3Key.
+func (c *Client) GetTrackDownloadLink(ctx context.Context, recordingID, trackID string) (*GetRecordingLinkResponse, error) {
+	resp := &GetRecordingLinkResponse{}
+	q := queryValues(recordingLinkParams{Track: trackID})
+	return resp, c.request(ctx, "GET", withQuery("recordings/"+recordingID+"/access-link", q), nil, resp)
+}
+
+// GetRecordingLinkV2 is like GetRecordingLink, but returns the link parsed.
+func (c *Client) GetRecordingLinkV2(ctx context.Context, recordingID string) (*RecordingLink, error) {
+	resp, err := c.GetRecordingLink(ctx, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	return resp.RecordingLink()
+}
+
+// GetRecordingLinkWithTTL returns a download link for a recording which stays
+// valid for the given duration instead of the server's default.
+func (c *Client) GetRecordingLinkWithTTL(ctx context.Context, recordingID string, validFor time.Duration) (*GetRecordingLinkResponse, error) {
+	resp := &GetRecordingLinkResponse{}
+	q := queryValues(recordingLinkParams{ValidForSecs: int64(validFor / time.Second)})
+	return resp, c.request(ctx, "GET", withQuery("recordings/"+recordingID+"/access-link", q), nil, resp)
+}
+
+// WaitForRecordingLink polls GetRecordingLink every pollInterval until the
+// recording has finished processing and a link is available. Hard failures are
+// returned immediately; otherwise it gives up when ctx is done.
+func (c *Client) WaitForRecordingLink(ctx context.Context, recordingID string, pollInterval time.Duration) (*GetRecordingLinkResponse, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		resp, err := c.GetRecordingLink(ctx, recordingID)
+		if e, ok := err.(Error); !ok || e.Message != ErrRecordingNotReady {
+			return resp, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("daily: recording %s not ready: %w", recordingID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordingLinkParams are the query parameters of a recording's access link.
+type recordingLinkParams struct {
+	ValidForSecs int64  `url:"valid_for_secs,omitempty"`
+	Track        string `url:"track,omitempty"`
+}
+
+// call holds per-call request adjustments and response metadata for methods
+// which need more than request offers.
+type call struct {
+	header http.Header // Added to the request
+
+	status     int         // Set to the response status
+	respHeader http.Header // Set to the response headers
 }
 
 func (c *Client) request(ctx context.Context, method, path string, data interface{}, result interface{}) error {
+	return c.requestCall(ctx, nil, method, path, data, result)
+}
+
+// defaultTimeout bounds requests whose context has no deadline. It is a
+// variable so tests can shorten it.
+var defaultTimeout = 5 * time.Second
+
+// requestCall is like request, but applies cl, if not nil. A 304 Not Modified
+// response isn't treated as an error when cl is given.
+func (c *Client) requestCall(ctx context.Context, cl *call, method, path string, data interface{}, result interface{}) error {
+	co := callOptionsFrom(ctx)
+	if co != nil && co.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, co.timeout)
+		defer cancel()
+	}
+
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	// Deadlines come from the context rather than http.Client.Timeout, so a
+	// caller can give slow calls longer than the default.
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
+		defer cancel()
+	}
+
 	rel, err := url.Parse(path)
 	if err != nil {
 		return fmt.Errorf("daily: failed to parse request path: %s", err)
@@ -194,22 +1085,70 @@ func (c *Client) request(ctx context.Context, method, path string, data interfac
 		return fmt.Errorf("daily: failed to build request: %s", err)
 	}
 
+	for k, v := range c.headers {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if co != nil {
+		for k, v := range co.header {
+			req.Header[k] = append([]string(nil), v...)
+		}
+	}
 	req.Header.Set("User-Agent", c.UserAgent)
-	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+	req.Header.Set("Accept", "application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key, ok := ctx.Value(idempotencyKeyCtx{}).(string); ok && key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	if c.correlationID != nil {
+		if id := c.correlationID(ctx); id != "" {
+			req.Header.Set("X-Correlation-ID", id)
+		}
+	}
+	if cl != nil {
+		for k, v := range cl.header {
+			req.Header[k] = append([]string(nil), v...)
+		}
+	}
+	if c.dryRun != nil {
+		c.dryRun(req.WithContext(ctx))
+		return Error{Message: ErrDryRun}
+	}
+	resp, err := chain(c.HTTPClient, c.layers).Do(req.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("daily: request failed: %s", err)
 	}
 	defer resp.Body.Close()
+	limited := &maxBytesReader{r: resp.Body, n: c.maxResponseBytes}
 
-	respBody, _ := ioutil.ReadAll(resp.Body)
+	if cl != nil {
+		cl.status = resp.StatusCode
+		cl.respHeader = resp.Header
+		if resp.StatusCode == http.StatusNotModified {
+			return nil
+		}
+	}
 
 	if resp.StatusCode != http.StatusOK {
+		respBody, err := ioutil.ReadAll(limited)
+		if err == errResponseTooLarge {
+			return Error{Message: ErrResponseTooLarge, StatusCode: resp.StatusCode}
+		}
 		var msg string
 		switch resp.StatusCode {
 		case http.StatusBadRequest:
 			msg = ErrBadRequest
 		case http.StatusUnauthorized:
 			msg = ErrUnauthorized
+		case http.StatusPaymentRequired:
+			msg = ErrPaymentRequired
+		case http.StatusForbidden:
+			msg = ErrForbidden
+		case http.StatusNotFound:
+			msg = ErrNotFound
+		case http.StatusConflict:
+			msg = ErrConflict
 		case http.StatusTooManyRequests:
 			msg = ErrTooManyRequests
 		case http.StatusInternalServerError:
@@ -217,6 +1156,20 @@ func (c *Client) request(ctx context.Context, method, path string, data interfac
 		default:
 			msg = ErrUnexpected
 		}
+		// Gateways in front of Daily may answer with an HTML error page.
+		if !isJSON(resp.Header) {
+			if resp.StatusCode >= 500 {
+				msg = ErrUpstreamGateway
+			}
+			if len(respBody) > maxRawDetails {
+				respBody = append(respBody[:maxRawDetails:maxRawDetails], "..."...)
+			}
+			return Error{
+				Message:    msg,
+				StatusCode: resp.StatusCode,
+				RawDetails: string(respBody),
+			}
+		}
 		details := &ErrorDetails{}
 		if err := json.Unmarshal(respBody, details); err != nil {
 			details = nil
@@ -229,13 +1182,65 @@ func (c *Client) request(ctx context.Context, method, path string, data interfac
 		}
 	}
 
-	if err = json.Unmarshal(respBody, result); err != nil {
-		return Error{
-			Message:    ErrParseError + ": " + err.Error(),
-			StatusCode: resp.StatusCode,
-			RawDetails: string(respBody),
+	// The body is always read to completion so the underlying connection can
+	// be returned to the transport's pool and reused by later requests.
+	defer io.Copy(ioutil.Discard, limited)
+	if result == nil {
+		return nil
+	}
+
+	// List responses are decoded an item at a time straight from the body, so
+	// a large page is never held in memory as raw JSON. Only the start of the
+	// body is kept for parse errors.
+	if sd, ok := result.(streamDecoder); ok {
+		head := &prefixBuffer{n: 1 << 10}
+		dec := json.NewDecoder(io.TeeReader(limited, head))
+		if c.strictDecoding {
+			dec.DisallowUnknownFields()
+		}
+		err = sd.decodeStream(dec, c.strictDecoding)
+		// An empty body leaves result untouched.
+		if err == io.EOF {
+			return nil
 		}
+		return decodeError(err, resp.StatusCode, head.buf)
 	}
 
-	return nil
+	respBody, err := ioutil.ReadAll(limited)
+	if err == errResponseTooLarge {
+		return Error{Message: ErrResponseTooLarge, StatusCode: resp.StatusCode}
+	} else if err != nil {
+		return fmt.Errorf("daily: request failed: %s", err)
+	}
+	// An empty or whitespace-only body leaves result untouched.
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(respBody))
+	if c.strictDecoding {
+		dec.DisallowUnknownFields()
+	}
+	return decodeError(dec.Decode(result), resp.StatusCode, respBody)
+}
+
+// decodeError converts an error decoding a successful response into an Error.
+// raw is as much of the body as was kept.
+func decodeError(err error, status int, raw []byte) error {
+	switch {
+	case err == nil:
+		return nil
+	case err == errResponseTooLarge:
+		return Error{Message: ErrResponseTooLarge, StatusCode: status}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return Error{
+			Message:    ErrUnknownField,
+			StatusCode: status,
+			RawDetails: strings.TrimPrefix(err.Error(), "json: unknown field "),
+		}
+	}
+	return Error{
+		Message:    ErrParseError + ": " + err.Error(),
+		StatusCode: status,
+		RawDetails: string(raw),
+	}
 }
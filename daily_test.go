@@ -0,0 +1,1648 @@
+package daily
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client whose requests go to a test server running
+// handler. Responses are JSON unless handler sets another Content-Type.
+// Retries, if enabled by opts, don't sleep between attempts.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(opts...)
+	u, err := url.Parse(srv.URL + "/v1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.BaseURL = *u
+	if c.retry != nil {
+		c.retry.sleep = func(context.Context, time.Duration) error { return nil }
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// testClock is a manually advanced clock for WithClock.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Unix(1600000000, 0)}
+}
+
+func (tc *testClock) Now() time.Time {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.now
+}
+
+func (tc *testClock) Advance(d time.Duration) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.now = tc.now.Add(d)
+}
+
+func TestGetRoomWithPresence(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/rooms/standup":
+			w.Write([]byte(`{"id":"room-id","name":"standup"}`))
+		case "/v1/rooms/standup/presence":
+			w.Write([]byte(`{"total_count":1,"data":[{"id":"p1","userName":"Ada"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	room, participants, err := c.GetRoomWithPresence(context.Background(), "standup")
+	if err != nil {
+		t.Fatalf("GetRoomWithPresence: %v", err)
+	}
+	if room.ID != "room-id" {
+		t.Errorf("room ID = %q, want room-id", room.ID)
+	}
+	if len(participants) != 1 || participants[0].UserName != "Ada" {
+		t.Errorf("participants = %+v, want Ada", participants)
+	}
+	if len(paths) != 2 {
+		t.Errorf("got requests %v, want room and presence", paths)
+	}
+}
+
+func TestGetRoomWithPresenceReturnsError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/rooms/gone" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not-found","info":"room gone not found"}`))
+			return
+		}
+		w.Write([]byte(`{"total_count":0,"data":[]}`))
+	})
+
+	_, _, err := c.GetRoomWithPresence(context.Background(), "gone")
+	if e, ok := err.(Error); !ok || e.Message != ErrNotFound {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"room","brand_new_field":true}`))
+	}
+
+	lenient := newTestClient(t, handler)
+	room, err := lenient.GetRoom(context.Background(), "room")
+	if err != nil {
+		t.Fatalf("default decoding: %v", err)
+	}
+	if room.Name != "room" {
+		t.Errorf("Name = %q, want room", room.Name)
+	}
+
+	strict := newTestClient(t, handler, WithStrictDecoding())
+	_, err = strict.GetRoom(context.Background(), "room")
+	e, ok := err.(Error)
+	if !ok || e.Message != ErrUnknownField {
+		t.Fatalf("strict decoding: err = %v, want ErrUnknownField", err)
+	}
+	if e.RawDetails != `"brand_new_field"` {
+		t.Errorf("RawDetails = %q, want the field name", e.RawDetails)
+	}
+}
+
+func TestCreateRoomGeo(t *testing.T) {
+	var body struct {
+		Properties map[string]interface{} `json:"properties"`
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		w.Write([]byte(`{"name":"room"}`))
+	})
+
+	req := &CreateRoomRequest{Config: &RoomConfig{Geo: String(GeoEUCentral1)}}
+	if _, err := c.CreateRoom(context.Background(), req); err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	if body.Properties["geo"] != "eu-central-1" {
+		t.Errorf("geo = %v, want eu-central-1", body.Properties["geo"])
+	}
+}
+
+func TestCreateRoomRejectsUnknownGeo(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent for an invalid config")
+	})
+
+	req := &CreateRoomRequest{Config: &RoomConfig{Geo: String("moon-base-1")}}
+	_, err := c.CreateRoom(context.Background(), req)
+	if e, ok := err.(Error); !ok || !strings.HasPrefix(e.Message, ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation", err)
+	}
+}
+
+func TestWaitForRecordingLink(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid-request-error","info":"recording is still processing"}`))
+			return
+		}
+		w.Write([]byte(`{"download_link":"https://example.com/rec.mp4","expires":1600000000}`))
+	})
+
+	resp, err := c.WaitForRecordingLink(context.Background(), "rec", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForRecordingLink: %v", err)
+	}
+	if resp.DownloadLink != "https://example.com/rec.mp4" {
+		t.Errorf("DownloadLink = %q", resp.DownloadLink)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls, want 3", calls)
+	}
+}
+
+func TestWaitForRecordingLinkHardFailure(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"not-found","info":"no such recording"}`))
+	})
+
+	_, err := c.WaitForRecordingLink(context.Background(), "rec", time.Millisecond)
+	if e, ok := err.(Error); !ok || e.Message != ErrNotFound {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
+
+// roomServer fakes room creation and lookup, rejecting duplicate names.
+func roomServer(t *testing.T) http.HandlerFunc {
+	var (
+		mu    sync.Mutex
+		rooms = map[string]bool{}
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/rooms":
+			var req CreateRoomRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decoding request: %v", err)
+			}
+			if rooms[*req.Name] {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error":"invalid-request-error","info":"a room named ` + *req.Name + ` already exists"}`))
+				return
+			}
+			rooms[*req.Name] = true
+			json.NewEncoder(w).Encode(Room{ID: "id-" + *req.Name, Name: *req.Name})
+		case r.Method == "GET" && rooms[strings.TrimPrefix(r.URL.Path, "/v1/rooms/")]:
+			name := strings.TrimPrefix(r.URL.Path, "/v1/rooms/")
+			json.NewEncoder(w).Encode(Room{ID: "id-" + name, Name: name})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not-found"}`))
+		}
+	}
+}
+
+func TestCreateRoomConflict(t *testing.T) {
+	c := newTestClient(t, roomServer(t))
+	req := &CreateRoomRequest{Name: String("standup")}
+
+	if _, err := c.CreateRoom(context.Background(), req); err != nil {
+		t.Fatalf("first CreateRoom: %v", err)
+	}
+	_, err := c.CreateRoom(context.Background(), req)
+	if e, ok := err.(Error); !ok || e.Message != ErrConflict || e.StatusCode != http.StatusConflict {
+		t.Fatalf("second CreateRoom: err = %v, want ErrConflict", err)
+	}
+}
+
+func TestGetOrCreateRoom(t *testing.T) {
+	c := newTestClient(t, roomServer(t))
+
+	created, err := c.GetOrCreateRoom(context.Background(), "standup", nil)
+	if err != nil {
+		t.Fatalf("creating: %v", err)
+	}
+	existing, err := c.GetOrCreateRoom(context.Background(), "standup", nil)
+	if err != nil {
+		t.Fatalf("getting existing: %v", err)
+	}
+	if existing.ID != created.ID {
+		t.Errorf("got room %q, want %q", existing.ID, created.ID)
+	}
+}
+
+func TestGetOrCreateRoomErrors(t *testing.T) {
+	tests := map[string]struct {
+		createStatus, getStatus int
+		want                    string
+	}{
+		"invalid config": {http.StatusBadRequest, http.StatusOK, ErrBadRequest},
+		"get fails":      {http.StatusConflict, http.StatusTooManyRequests, ErrTooManyRequests},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "POST" {
+					w.WriteHeader(tt.createStatus)
+					w.Write([]byte(`{"error":"invalid-request-error","info":"rejected"}`))
+					return
+				}
+				w.WriteHeader(tt.getStatus)
+				w.Write([]byte(`{"name":"standup"}`))
+			})
+
+			_, err := c.GetOrCreateRoom(context.Background(), "standup", nil)
+			if e, ok := err.(Error); !ok || e.Message != tt.want {
+				t.Fatalf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONHeaders(t *testing.T) {
+	headers := map[string]http.Header{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		headers[r.Method] = r.Header
+		w.Write([]byte(`{"name":"room"}`))
+	})
+
+	if _, err := c.CreateRoom(context.Background(), &CreateRoomRequest{}); err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	if _, err := c.GetRoom(context.Background(), "room"); err != nil {
+		t.Fatalf("GetRoom: %v", err)
+	}
+
+	if got := headers["POST"].Get("Content-Type"); got != "application/json" {
+		t.Errorf("POST Content-Type = %q, want application/json", got)
+	}
+	if got := headers["GET"].Get("Content-Type"); got != "" {
+		t.Errorf("GET Content-Type = %q, want none", got)
+	}
+	for method, h := range headers {
+		if got := h.Get("Accept"); got != "application/json" {
+			t.Errorf("%s Accept = %q, want application/json", method, got)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	var sent *http.Request
+	var body []byte
+	c := New(WithAuth("secret"), WithDryRun(func(r *http.Request) {
+		sent = r
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+
+	_, err := c.CreateRoom(context.Background(), &CreateRoomRequest{
+		Name:    String("standup"),
+		Privacy: Private,
+	})
+	if e, ok := err.(Error); !ok || e.Message != ErrDryRun {
+		t.Fatalf("err = %v, want ErrDryRun", err)
+	}
+	if sent == nil {
+		t.Fatal("dry run func not called")
+	}
+	if sent.Method != "POST" || sent.URL.String() != "https://api.daily.co/v1/rooms" {
+		t.Errorf("request = %s %s, want POST https://api.daily.co/v1/rooms", sent.Method, sent.URL)
+	}
+	if want := `{"name":"standup","privacy":"private"}`; string(body) != want {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+}
+
+// pageBounds returns the range of the n items, identified by id, on the page
+// requested by q's limit and starting_after, capping pages at 100 like Daily.
+func pageBounds(n int, id func(int) string, q url.Values) (from, to int) {
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	if after := q.Get("starting_after"); after != "" {
+		for from < n && id(from) != after {
+			from++
+		}
+		from++
+	}
+	if from > n {
+		from = n
+	}
+	to = from + limit
+	if to > n {
+		to = n
+	}
+	return from, to
+}
+
+func TestGetParticipantMinutes(t *testing.T) {
+	sessions := []MeetingSession{
+		{ID: "s0", Participants: []MeetingParticipant{{UserID: "ada", JoinTime: 0, Duration: 600}}},
+		{ID: "s1", Participants: []MeetingParticipant{{UserID: "ada", JoinTime: 300, Duration: 600}}},
+	}
+	for i := 2; i < 150; i++ {
+		sessions = append(sessions, MeetingSession{
+			ID:           "s" + strconv.Itoa(i),
+			Participants: []MeetingParticipant{{ParticipantID: "guest", JoinTime: int64(i) * 1000, Duration: 60}},
+		})
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		from, to := pageBounds(len(sessions), func(i int) string { return sessions[i].ID }, r.URL.Query())
+		json.NewEncoder(w).Encode(GetMeetingsResponse{TotalCount: len(sessions), Meetings: sessions[from:to]})
+	})
+
+	minutes, err := c.GetParticipantMinutes(context.Background(), GetMeetingsParams{Limit: 500})
+	if err != nil {
+		t.Fatalf("GetParticipantMinutes: %v", err)
+	}
+	if got, want := minutes["ada"], 15*time.Minute; got != want {
+		t.Errorf("ada: got %s, want %s for overlapping sessions", got, want)
+	}
+	if got, want := minutes["guest"], 148*time.Minute; got != want {
+		t.Errorf("guest: got %s, want %s across pages", got, want)
+	}
+}
+
+// recordingServer pages through recordings, which are served in the given
+// order, and records the limit of each request.
+func recordingServer(recordings []Recording, limits *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		*limits = append(*limits, q.Get("limit"))
+		from, to := pageBounds(len(recordings), func(i int) string { return recordings[i].Id }, q)
+		json.NewEncoder(w).Encode(GetRecordingResponse{TotalCount: len(recordings), Recording: recordings[from:to]})
+	}
+}
+
+func TestGetRecordingsAll(t *testing.T) {
+	var recordings []Recording
+	for i := 0; i < 250; i++ {
+		recordings = append(recordings, Recording{Id: "r" + strconv.Itoa(i), StartTs: 1000 - i})
+	}
+	var limits []string
+	c := newTestClient(t, recordingServer(recordings, &limits))
+
+	all, err := c.GetRecordings(context.Background(), GetRecordingsParams{Limit: -1})
+	if err != nil {
+		t.Fatalf("GetRecordings: %v", err)
+	}
+	if all.TotalCount != 250 || len(all.Recording) != 250 {
+		t.Fatalf("got %d recordings (total %d), want 250", len(all.Recording), all.TotalCount)
+	}
+	for i, r := range all.Recording {
+		if r.Id != recordings[i].Id {
+			t.Fatalf("recording %d is %s, want %s", i, r.Id, recordings[i].Id)
+		}
+	}
+	if len(limits) != 3 {
+		t.Errorf("got %d page requests, want 3", len(limits))
+	}
+}
+
+func TestListRecordingsSince(t *testing.T) {
+	var recordings []Recording
+	for i := 0; i < 300; i++ {
+		recordings = append(recordings, Recording{Id: "r" + strconv.Itoa(i), StartTs: 10000 - i})
+	}
+	var limits []string
+	c := newTestClient(t, recordingServer(recordings, &limits))
+
+	since := time.Unix(10000-150, 0)
+	got, err := c.ListRecordingsSince(context.Background(), since)
+	if err != nil {
+		t.Fatalf("ListRecordingsSince: %v", err)
+	}
+	if len(got) != 151 {
+		t.Fatalf("got %d recordings, want 151", len(got))
+	}
+	if last := got[len(got)-1]; int64(last.StartTs) != since.Unix() {
+		t.Errorf("oldest recording started at %d, want %d", last.StartTs, since.Unix())
+	}
+	if len(limits) != 2 {
+		t.Errorf("got %d page requests, want 2: paging should stop at the cutoff", len(limits))
+	}
+}
+
+func TestTimeWindowValidatedBeforeSending(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request sent for %s %s", r.Method, r.URL.Path)
+	})
+	ctx := context.Background()
+
+	_, err := c.CreateRoom(ctx, &CreateRoomRequest{Config: &RoomConfig{NotBefore: Int64(200), ExpiresAt: Int64(100)}})
+	if e, ok := err.(Error); !ok || !strings.HasPrefix(e.Message, ErrInvalidTimeWindow) {
+		t.Errorf("CreateRoom: err = %v, want ErrInvalidTimeWindow", err)
+	}
+	_, err = c.UpdateRoom(ctx, "room", &UpdateRoomRequest{Config: &RoomConfig{NotBefore: Int64(100), ExpiresAt: Int64(100)}})
+	if e, ok := err.(Error); !ok || !strings.HasPrefix(e.Message, ErrInvalidTimeWindow) {
+		t.Errorf("UpdateRoom: err = %v, want ErrInvalidTimeWindow", err)
+	}
+	_, err = c.CreateMeetingToken(ctx, &CreateMeetingTokenRequest{Properties: &MeetingToken{NotBefore: Int64(200), ExpiresAt: Int64(100)}})
+	if e, ok := err.(Error); !ok || !strings.HasPrefix(e.Message, ErrInvalidTimeWindow) {
+		t.Errorf("CreateMeetingToken: err = %v, want ErrInvalidTimeWindow", err)
+	}
+}
+
+func TestWithToken(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		auths []string
+	)
+	base := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.Write([]byte(`{"domain_name":"example"}`))
+	}, WithAuth("base"))
+	tenantA := base.WithToken("token-a")
+	tenantB := base.WithToken("token-b")
+
+	for _, c := range []*Client{tenantA, tenantB, base} {
+		if _, err := c.GetDomainConfig(context.Background()); err != nil {
+			t.Fatalf("GetDomainConfig: %v", err)
+		}
+	}
+	want := []string{"Bearer token-a", "Bearer token-b", "Bearer base"}
+	if !reflect.DeepEqual(auths, want) {
+		t.Errorf("Authorization headers = %q, want %q", auths, want)
+	}
+	if tenantA.HTTPClient != base.HTTPClient {
+		t.Error("derived client doesn't share the base HTTPClient")
+	}
+	if tenantA.domainCache == base.domainCache {
+		t.Error("derived client shares the base domain config cache")
+	}
+}
+
+// staticClient answers every request with body.
+type staticClient struct {
+	body []byte
+}
+
+func (s staticClient) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(s.body)),
+	}, nil
+}
+
+// largeRoomList returns the JSON of a page of n rooms.
+func largeRoomList(n int) []byte {
+	resp := ListRoomsResponse{TotalCount: int32(n)}
+	for i := 0; i < n; i++ {
+		resp.Rooms = append(resp.Rooms, Room{
+			ID:        "id-" + strconv.Itoa(i),
+			Name:      "room-" + strconv.Itoa(i),
+			Privacy:   Private,
+			URL:       "https://example.daily.co/room-" + strconv.Itoa(i),
+			CreatedAt: time.Unix(1600000000, 0).UTC(),
+			Config:    &RoomConfig{MaxParticipants: Int32(10), EnableChat: True(), Geo: String(GeoUSEast1)},
+		})
+	}
+	body, _ := json.Marshal(resp)
+	return body
+}
+
+func TestStreamingDecodeMatchesBuffered(t *testing.T) {
+	body := largeRoomList(1000)
+	c := New()
+	c.HTTPClient = staticClient{body}
+
+	streamed, err := c.ListRooms(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListRooms: %v", err)
+	}
+	buffered := &ListRoomsResponse{}
+	if err := json.Unmarshal(body, buffered); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(streamed, buffered) {
+		t.Error("streamed response differs from unmarshaling the buffered body")
+	}
+}
+
+func TestListRoomsDecodeEdgeCases(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		body   string
+		strict bool
+		want   *ListRoomsResponse
+		err    string
+	}{
+		{name: "empty body", body: "", want: &ListRoomsResponse{}},
+		{name: "empty page", body: `{"total_count":0,"data":[]}`, want: &ListRoomsResponse{Rooms: []Room{}}},
+		{name: "null data", body: `{"total_count":0,"data":null}`, want: &ListRoomsResponse{}},
+		{
+			name: "unknown fields skipped",
+			body: `{"total_count":1,"paging":{"next":"x"},"data":[{"name":"a","extra":[1,2]}]}`,
+			want: &ListRoomsResponse{TotalCount: 1, Rooms: []Room{{Name: "a"}}},
+		},
+		{name: "unknown top-level field in strict mode", body: `{"total_count":1,"paging":{},"data":[]}`, strict: true, err: ErrUnknownField},
+		{name: "unknown room field in strict mode", body: `{"total_count":1,"data":[{"name":"a","extra":1}]}`, strict: true, err: ErrUnknownField},
+		{name: "malformed item", body: `{"total_count":2,"data":[{"name":"a"},{"name":1}]}`, err: ErrParseError},
+		{name: "truncated", body: `{"total_count":2,"data":[{"name":"a"}`, err: ErrParseError},
+		{name: "not an object", body: `[]`, err: ErrParseError},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.strict {
+				opts = append(opts, WithStrictDecoding())
+			}
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}, opts...)
+
+			got, err := c.ListRooms(context.Background(), nil)
+			if tt.err != "" {
+				if e, ok := err.(Error); !ok || !strings.HasPrefix(e.Message, tt.err) {
+					t.Fatalf("ListRooms err = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListRooms: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListRooms = %+v, want %+v", got, tt.want)
+			}
+			// The item-at-a-time decode should agree with json.Unmarshal.
+			if tt.body != "" {
+				want := &ListRoomsResponse{}
+				json.Unmarshal([]byte(tt.body), want)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("ListRooms = %+v, json.Unmarshal gives %+v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestListRoomsParseErrorKeepsHead(t *testing.T) {
+	body := largeRoomList(100)
+	body = append(body[:len(body)-2], ",1]}"...)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	})
+
+	_, err := c.ListRooms(context.Background(), nil)
+	e, ok := err.(Error)
+	if !ok || !strings.HasPrefix(e.Message, ErrParseError) {
+		t.Fatalf("ListRooms err = %v, want %s", err, ErrParseError)
+	}
+	if e.RawDetails != string(body[:1<<10]) {
+		t.Errorf("RawDetails = %q, want the first 1KiB of the body", e.RawDetails)
+	}
+}
+
+func TestParseErrorKeepsBody(t *testing.T) {
+	body := `{"name":"room","config":{"max_participants":"ten"}}` + strings.Repeat(" ", 2<<10)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+
+	_, err := c.GetRoom(context.Background(), "room")
+	e, ok := err.(Error)
+	if !ok || !strings.HasPrefix(e.Message, ErrParseError) {
+		t.Fatalf("GetRoom err = %v, want %s", err, ErrParseError)
+	}
+	if e.RawDetails != body {
+		t.Errorf("RawDetails has %d bytes, want the whole %d byte body", len(e.RawDetails), len(body))
+	}
+}
+
+func BenchmarkListRooms(b *testing.B) {
+	body := largeRoomList(1000)
+	c := New()
+	c.HTTPClient = staticClient{body}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	for i := 0; i < b.N; i++ {
+		if _, err := c.ListRooms(context.Background(), nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecode compares request's item-at-a-time decode of a list response
+// with reading the whole body and unmarshaling it. The decoder only ever holds
+// one room's raw JSON, so streaming allocates a little over half the bytes,
+// though in slightly more allocations.
+func BenchmarkDecode(b *testing.B) {
+	body := largeRoomList(1000)
+	b.Run("streaming", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			head := &prefixBuffer{n: 1 << 10}
+			dec := json.NewDecoder(io.TeeReader(bytes.NewReader(body), head))
+			if err := (&ListRoomsResponse{}).decodeStream(dec, false); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			data, err := ioutil.ReadAll(bytes.NewReader(body))
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := json.Unmarshal(data, &ListRoomsResponse{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestProbe(t *testing.T) {
+	fixed := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.RawQuery; got != "limit=1" {
+			t.Errorf("query = %q, want limit=1", got)
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("X-RateLimit-Limit", "20")
+		w.Header().Set("X-RateLimit-Remaining", "17")
+		w.Write([]byte(`{"total_count":1,"data":[{"name":"room"}]}`))
+	}, WithClock(func() time.Time { return fixed }))
+
+	res, err := c.Probe(context.Background())
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", res.StatusCode)
+	}
+	if res.Latency < 20*time.Millisecond {
+		t.Errorf("Latency = %s under a fixed clock, want at least the handler's 20ms", res.Latency)
+	}
+	if want := (&RateLimit{Limit: 20, Remaining: 17}); !reflect.DeepEqual(res.RateLimit, want) {
+		t.Errorf("RateLimit = %+v, want %+v", res.RateLimit, want)
+	}
+}
+
+func TestProbeErrors(t *testing.T) {
+	for status, want := range map[int]string{
+		http.StatusUnauthorized:    ErrUnauthorized,
+		http.StatusForbidden:       ErrForbidden,
+		http.StatusTooManyRequests: ErrTooManyRequests,
+	} {
+		t.Run(want, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+				w.Write([]byte(`{"error":"nope"}`))
+			})
+
+			res, err := c.Probe(context.Background())
+			if e, ok := err.(Error); !ok || e.Message != want {
+				t.Fatalf("Probe err = %v, want %s", err, want)
+			}
+			if res.StatusCode != status {
+				t.Errorf("StatusCode = %d, want %d", res.StatusCode, status)
+			}
+			if res.RateLimit != nil {
+				t.Errorf("RateLimit = %+v without rate limit headers, want nil", res.RateLimit)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyHeader(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		keys = map[string]string{}
+	)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys[r.URL.Path] = r.Header.Get("Idempotency-Key")
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/meeting-tokens":
+			w.Write([]byte(`{"token":"abc"}`))
+		default:
+			w.Write([]byte(`{"name":"standup"}`))
+		}
+	})
+	ctx := context.Background()
+
+	if _, err := c.CreateRoom(WithIdempotencyKey(ctx, "room-key"), &CreateRoomRequest{Name: String("standup")}); err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	if _, err := c.CreateMeetingToken(WithIdempotencyKey(ctx, "token-key"), &CreateMeetingTokenRequest{}); err != nil {
+		t.Fatalf("CreateMeetingToken: %v", err)
+	}
+	if _, err := c.GetRoom(ctx, "standup"); err != nil {
+		t.Fatalf("GetRoom: %v", err)
+	}
+	want := map[string]string{
+		"/v1/rooms":          "room-key",
+		"/v1/meeting-tokens": "token-key",
+		"/v1/rooms/standup":  "",
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("Idempotency-Key headers = %v, want %v", keys, want)
+	}
+}
+
+func TestRetryCreateAfterTimeout(t *testing.T) {
+	for _, honorsKey := range []bool{true, false} {
+		name := "server ignores key"
+		if honorsKey {
+			name = "server honors key"
+		}
+		t.Run(name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				calls int
+				byKey = map[string]bool{}
+			)
+			rooms := roomServer(t)
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				key := r.Header.Get("Idempotency-Key")
+				seen := byKey[key]
+				byKey[key] = true
+				calls++
+				first := calls == 1
+				mu.Unlock()
+				if r.Method == "POST" && honorsKey && seen {
+					w.Write([]byte(`{"id":"id-standup","name":"standup"}`))
+					return
+				}
+				rooms(w, r)
+				if first {
+					// The room is created, but the response arrives too late.
+					time.Sleep(200 * time.Millisecond)
+				}
+			})
+			ctx := WithIdempotencyKey(context.Background(), "create-standup")
+
+			timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+			defer cancel()
+			if _, err := c.GetOrCreateRoom(timeout, "standup", nil); err == nil {
+				t.Fatal("first GetOrCreateRoom succeeded, want a timeout")
+			}
+
+			room, err := c.GetOrCreateRoom(ctx, "standup", nil)
+			if err != nil {
+				t.Fatalf("retried GetOrCreateRoom: %v", err)
+			}
+			if room.ID != "id-standup" {
+				t.Errorf("room ID = %q, want id-standup", room.ID)
+			}
+		})
+	}
+}
+
+func TestContextDeadlineOverridesDefaultTimeout(t *testing.T) {
+	defer func(d time.Duration) { defaultTimeout = d }(defaultTimeout)
+	defaultTimeout = 50 * time.Millisecond
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{"name":"room"}`))
+	})
+
+	if _, err := c.GetRoom(context.Background(), "room"); err == nil {
+		t.Error("GetRoom without a deadline outlived the default timeout")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := c.GetRoom(ctx, "room"); err != nil {
+		t.Errorf("GetRoom with a generous deadline: %v", err)
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	var got http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		w.Write([]byte(`{"name":"room"}`))
+	},
+		WithAuth("secret"),
+		WithHeader("X-Gateway-Key", "gw"),
+		WithHeader("X-Route", "a"),
+		WithHeader("X-Route", "b"),
+		WithHeader("User-Agent", "spoofed"),
+		WithHeader("Authorization", "Bearer spoofed"),
+	)
+
+	if _, err := c.GetRoom(context.Background(), "room"); err != nil {
+		t.Fatalf("GetRoom: %v", err)
+	}
+	if v := got.Get("X-Gateway-Key"); v != "gw" {
+		t.Errorf("X-Gateway-Key = %q, want gw", v)
+	}
+	if v := got["X-Route"]; !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("X-Route = %q, want [a b]", v)
+	}
+	if v := got["User-Agent"]; !reflect.DeepEqual(v, []string{userAgent}) {
+		t.Errorf("User-Agent = %q, want %q", v, userAgent)
+	}
+	if v := got["Authorization"]; !reflect.DeepEqual(v, []string{"Bearer secret"}) {
+		t.Errorf("Authorization = %q, want the client's token", v)
+	}
+}
+
+func TestWithAPIVersion(t *testing.T) {
+	for _, tt := range []struct {
+		opts []Option
+		base string
+	}{
+		{nil, "https://api.daily.co/v1/"},
+		{[]Option{WithAPIVersion("v1")}, "https://api.daily.co/v1/"},
+		{[]Option{WithAPIVersion("v2")}, "https://api.daily.co/v2/"},
+		{[]Option{WithAPIVersion("v2"), WithAPIVersion("v3")}, "https://api.daily.co/v3/"},
+	} {
+		t.Run(tt.base, func(t *testing.T) {
+			var urls []string
+			opts := append(tt.opts, WithDryRun(func(r *http.Request) {
+				urls = append(urls, r.URL.String())
+			}))
+			c := New(opts...)
+
+			c.GetRoom(context.Background(), "standup")
+			c.GetRecordings(context.Background(), GetRecordingsParams{})
+			want := []string{tt.base + "rooms/standup", tt.base + "recordings"}
+			if !reflect.DeepEqual(urls, want) {
+				t.Errorf("URLs = %v, want %v", urls, want)
+			}
+		})
+	}
+}
+
+type requestIDCtx struct{}
+
+func TestCorrelationID(t *testing.T) {
+	var got []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, strings.Join(r.Header["X-Correlation-Id"], ","))
+		w.Write([]byte(`{"name":"room"}`))
+	}, WithCorrelationIDFunc(func(ctx context.Context) string {
+		id, _ := ctx.Value(requestIDCtx{}).(string)
+		return id
+	}))
+
+	for _, id := range []string{"req-1", "req-2", ""} {
+		ctx := context.WithValue(context.Background(), requestIDCtx{}, id)
+		if _, err := c.GetRoom(ctx, "room"); err != nil {
+			t.Fatalf("GetRoom: %v", err)
+		}
+	}
+	if want := []string{"req-1", "req-2", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("X-Correlation-ID headers = %q, want %q", got, want)
+	}
+}
+
+func TestGetRoomConditional(t *testing.T) {
+	var ifNoneMatch []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		inm := r.Header.Get("If-None-Match")
+		ifNoneMatch = append(ifNoneMatch, inm)
+		if inm == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"name":"room"}`))
+	})
+	ctx := context.Background()
+
+	room, etag, notModified, err := c.GetRoomConditional(ctx, "room", "")
+	if err != nil {
+		t.Fatalf("first GetRoomConditional: %v", err)
+	}
+	if notModified || room == nil || room.Name != "room" || etag != `"v1"` {
+		t.Fatalf("first GetRoomConditional = %+v, %q, %v, want the room and its ETag", room, etag, notModified)
+	}
+
+	room, etag, notModified, err = c.GetRoomConditional(ctx, "room", etag)
+	if err != nil {
+		t.Fatalf("second GetRoomConditional: %v", err)
+	}
+	if !notModified || room != nil || etag != `"v1"` {
+		t.Errorf("second GetRoomConditional = %+v, %q, %v, want not modified with the same ETag", room, etag, notModified)
+	}
+	if want := []string{"", `"v1"`}; !reflect.DeepEqual(ifNoneMatch, want) {
+		t.Errorf("If-None-Match headers = %q, want %q", ifNoneMatch, want)
+	}
+}
+
+func TestNotModifiedIsAnErrorWithoutConditional(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+	if _, err := c.GetRoom(context.Background(), "room"); err == nil {
+		t.Error("GetRoom treated an unsolicited 304 as success")
+	}
+}
+
+func TestEmptySuccessBodies(t *testing.T) {
+	for _, body := range []string{"", "{}", " \n"} {
+		t.Run(strconv.Quote(body), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(body))
+			})
+			ctx := context.Background()
+
+			if err := c.StopRecording(ctx, "room"); err != nil {
+				t.Errorf("StopRecording: %v", err)
+			}
+			resp, err := c.StartRecording(ctx, "room", nil)
+			if err != nil {
+				t.Fatalf("StartRecording: %v", err)
+			}
+			if !reflect.DeepEqual(resp, &StartRecordingResponse{}) {
+				t.Errorf("StartRecording = %+v, want an empty response", resp)
+			}
+		})
+	}
+}
+
+func TestCreateMeetingTokenEmptyToken(t *testing.T) {
+	for _, body := range []string{"{}", `{"token":""}`} {
+		t.Run(body, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(body))
+			})
+
+			resp, err := c.CreateMeetingToken(context.Background(), &CreateMeetingTokenRequest{})
+			if e, ok := err.(Error); !ok || e.Message != ErrEmptyToken || e.StatusCode != http.StatusOK {
+				t.Fatalf("err = %v, want ErrEmptyToken", err)
+			}
+			if resp != nil {
+				t.Errorf("response = %+v, want nil", resp)
+			}
+		})
+	}
+}
+
+func TestCreateTemporaryRoom(t *testing.T) {
+	clock := newTestClock()
+	var got CreateRoomRequest
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		w.Write([]byte(`{"name":"temp"}`))
+	}, WithClock(clock.Now))
+
+	req := &CreateRoomRequest{Privacy: Private, Config: &RoomConfig{MaxParticipants: Int32(4), ExpiresAt: Int64(1)}}
+	if _, err := c.CreateTemporaryRoom(context.Background(), "temp", time.Hour, req); err != nil {
+		t.Fatalf("CreateTemporaryRoom: %v", err)
+	}
+	want := CreateRoomRequest{
+		Name:    String("temp"),
+		Privacy: Private,
+		Config: &RoomConfig{
+			MaxParticipants:   Int32(4),
+			ExpiresAt:         Int64(clock.Now().Add(time.Hour).Unix()),
+			EjectAtRoomExpiry: True(),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("request = %+v, want %+v", got, want)
+	}
+	if *req.Config.ExpiresAt != 1 || req.Config.EjectAtRoomExpiry != nil {
+		t.Error("CreateTemporaryRoom modified the caller's request")
+	}
+}
+
+func TestListRoomsMatching(t *testing.T) {
+	var rooms []Room
+	for i := 0; i < 250; i++ {
+		name := "other-" + strconv.Itoa(i)
+		if i%10 == 0 {
+			name = "standup-" + strconv.Itoa(i)
+		}
+		rooms = append(rooms, Room{ID: "id-" + strconv.Itoa(i), Name: name})
+	}
+	var pages int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		from, to := pageBounds(len(rooms), func(i int) string { return rooms[i].ID }, r.URL.Query())
+		json.NewEncoder(w).Encode(ListRoomsResponse{TotalCount: int32(len(rooms)), Rooms: rooms[from:to]})
+	})
+
+	got, err := c.ListRoomsMatching(context.Background(), func(r Room) bool {
+		return strings.HasPrefix(r.Name, "standup-")
+	})
+	if err != nil {
+		t.Fatalf("ListRoomsMatching: %v", err)
+	}
+	var names []string
+	for _, r := range got {
+		names = append(names, r.Name)
+	}
+	var want []string
+	for i := 0; i < 250; i += 10 {
+		want = append(want, "standup-"+strconv.Itoa(i))
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("rooms = %v, want %v", names, want)
+	}
+	if pages != 3 {
+		t.Errorf("fetched %d pages, want 3", pages)
+	}
+}
+
+func TestRecreateRoom(t *testing.T) {
+	for _, deleteFails := range []bool{false, true} {
+		t.Run(fmt.Sprintf("delete fails %v", deleteFails), func(t *testing.T) {
+			var (
+				created CreateRoomRequest
+				deleted []string
+			)
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "GET" && r.URL.Path == "/v1/rooms/old":
+					w.Write([]byte(`{"id":"old-id","name":"old","privacy":"private","config":{"max_participants":5,"enable_chat":true}}`))
+				case r.Method == "POST" && r.URL.Path == "/v1/rooms":
+					json.NewDecoder(r.Body).Decode(&created)
+					w.Write([]byte(`{"id":"new-id","name":"new","privacy":"private","config":{"max_participants":5,"enable_chat":true}}`))
+				case r.Method == "DELETE":
+					deleted = append(deleted, r.URL.Path)
+					if deleteFails {
+						w.WriteHeader(http.StatusInternalServerError)
+						w.Write([]byte(`{"error":"server-error"}`))
+						return
+					}
+					w.Write([]byte(`{"deleted":true}`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+			})
+
+			room, err := c.RecreateRoom(context.Background(), "old", "new")
+			want := CreateRoomRequest{
+				Name:    String("new"),
+				Privacy: Private,
+				Config:  &RoomConfig{MaxParticipants: Int32(5), EnableChat: True()},
+			}
+			if !reflect.DeepEqual(created, want) {
+				t.Errorf("create request = %+v, want %+v", created, want)
+			}
+			if !reflect.DeepEqual(deleted, []string{"/v1/rooms/old"}) {
+				t.Errorf("deleted %v, want the old room", deleted)
+			}
+			if room == nil || room.ID != "new-id" {
+				t.Errorf("room = %+v, want the new room", room)
+			}
+			if e, ok := err.(Error); deleteFails && (!ok || e.Message != ErrInternal) {
+				t.Errorf("err = %v, want the delete's ErrInternal", err)
+			} else if !deleteFails && err != nil {
+				t.Errorf("RecreateRoom: %v", err)
+			}
+		})
+	}
+}
+
+func TestExportImportRoomConfig(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": "room-id",
+			"name": "standup",
+			"api_created": true,
+			"privacy": "private",
+			"url": "https://example.daily.co/standup",
+			"created_at": "2021-01-01T00:00:00.000Z",
+			"config": {"max_participants": 5, "enable_chat": true, "exp": 1700000000, "lang": "de"}
+		}`))
+	})
+
+	room, err := c.GetRoom(context.Background(), "standup")
+	if err != nil {
+		t.Fatalf("GetRoom: %v", err)
+	}
+	data, err := room.ExportConfig()
+	if err != nil {
+		t.Fatalf("ExportConfig: %v", err)
+	}
+	for _, field := range []string{"room-id", "api_created", "example.daily.co", "created_at"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("export contains server-assigned %s: %s", field, data)
+		}
+	}
+	req, err := ImportRoomConfig(data)
+	if err != nil {
+		t.Fatalf("ImportRoomConfig: %v", err)
+	}
+	want := &CreateRoomRequest{Name: String("standup"), Privacy: Private, Config: room.Config}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("imported %+v, want %+v", req, want)
+	}
+
+	if _, err := ImportRoomConfig([]byte("not json")); err == nil {
+		t.Error("ImportRoomConfig accepted invalid JSON")
+	}
+}
+
+func TestCreateRoomFromTemplate(t *testing.T) {
+	var got []CreateRoomRequest
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req CreateRoomRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		got = append(got, req)
+		w.Write([]byte(`{"name":"room"}`))
+	})
+	tmpl := &RoomTemplate{
+		Privacy: Private,
+		Config:  RoomConfig{MaxParticipants: Int32(10), EnableChat: True(), Lang: String("en")},
+	}
+	ctx := context.Background()
+
+	if _, err := c.CreateRoomFromTemplate(ctx, tmpl, RoomName("big"), func(r *CreateRoomRequest) {
+		r.Config.MaxParticipants = Int32(50)
+	}); err != nil {
+		t.Fatalf("CreateRoomFromTemplate with override: %v", err)
+	}
+	if _, err := c.CreateRoomFromTemplate(ctx, tmpl, RoomName("plain")); err != nil {
+		t.Fatalf("CreateRoomFromTemplate: %v", err)
+	}
+
+	want := []CreateRoomRequest{
+		{Name: String("big"), Privacy: Private, Config: &RoomConfig{MaxParticipants: Int32(50), EnableChat: True(), Lang: String("en")}},
+		{Name: String("plain"), Privacy: Private, Config: &RoomConfig{MaxParticipants: Int32(10), EnableChat: True(), Lang: String("en")}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %+v, want %+v", got, want)
+	}
+	if *tmpl.Config.MaxParticipants != 10 {
+		t.Error("override changed the template")
+	}
+}
+
+func TestSendAppMessage(t *testing.T) {
+	tests := map[string]struct {
+		recipients []string
+		want       []string
+	}{
+		"broadcast": {nil, []string{"*"}},
+		"targeted":  {[]string{"session-a", "session-b"}, []string{"session-a", "session-b"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "POST" || r.URL.Path != "/v1/rooms/standup/send-app-message" {
+					t.Errorf("request = %s %s, want POST to send-app-message", r.Method, r.URL.Path)
+				}
+				var req SendAppMessageRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				if string(req.Data) != `{"kind":"ping"}` {
+					t.Errorf("data = %s, want the message", req.Data)
+				}
+				got = append(got, req.Recipient)
+				w.Write([]byte(`{"sent":true}`))
+			})
+
+			if err := c.SendAppMessage(context.Background(), "standup", json.RawMessage(`{"kind":"ping"}`), tt.recipients); err != nil {
+				t.Fatalf("SendAppMessage: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recipients = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRecordingsByStatus(t *testing.T) {
+	var recordings []Recording
+	for i := 0; i < 150; i++ {
+		status := RecordingFinished
+		if i%50 == 0 {
+			status = RecordingInProgress
+		}
+		recordings = append(recordings, Recording{Id: "r" + strconv.Itoa(i), Status: status})
+	}
+	var limits []string
+	c := newTestClient(t, recordingServer(recordings, &limits))
+
+	got, err := c.GetRecordingsByStatus(context.Background(), RecordingInProgress)
+	if err != nil {
+		t.Fatalf("GetRecordingsByStatus: %v", err)
+	}
+	var ids []string
+	for _, r := range got {
+		ids = append(ids, r.Id)
+	}
+	if want := []string{"r0", "r50", "r100"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("recordings = %v, want %v", ids, want)
+	}
+	if len(limits) != 2 {
+		t.Errorf("got %d page requests, want 2", len(limits))
+	}
+}
+
+func TestGetRecordingsLimit(t *testing.T) {
+	tests := map[string]struct {
+		limit int
+		want  string
+	}{
+		"default":   {0, ""},
+		"in range":  {25, "25"},
+		"maximum":   {100, "100"},
+		"too large": {500, "100"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var limits []string
+			c := newTestClient(t, recordingServer(nil, &limits))
+
+			if _, err := c.GetRecordings(context.Background(), GetRecordingsParams{Limit: tt.limit}); err != nil {
+				t.Fatalf("GetRecordings: %v", err)
+			}
+			if !reflect.DeepEqual(limits, []string{tt.want}) {
+				t.Errorf("limit sent = %q, want %q", limits, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRecordingLinkV2(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/recordings/bad/access-link" {
+			w.Write([]byte(`{"download_link":"http://[::1","expires":1}`))
+			return
+		}
+		w.Write([]byte(`{"download_link":"https://dl.example.com/rec.mp4?sig=abc","expires":1600003600}`))
+	})
+
+	link, err := c.GetRecordingLinkV2(context.Background(), "rec")
+	if err != nil {
+		t.Fatalf("GetRecordingLinkV2: %v", err)
+	}
+	if link.URL.Host != "dl.example.com" || link.URL.Query().Get("sig") != "abc" {
+		t.Errorf("URL = %s, want the parsed download link", link.URL)
+	}
+	if want := time.Unix(1600003600, 0); !link.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %s, want %s", link.ExpiresAt, want)
+	}
+	if link.IsExpired(time.Unix(1600000000, 0)) || !link.IsExpired(time.Unix(1600003600, 0)) {
+		t.Error("IsExpired doesn't flip at ExpiresAt")
+	}
+
+	_, err = c.GetRecordingLinkV2(context.Background(), "bad")
+	if e, ok := err.(Error); !ok || !strings.HasPrefix(e.Message, ErrParseError) {
+		t.Errorf("err = %v for an unparsable link, want %s", err, ErrParseError)
+	}
+}
+
+func TestGetRecordingTracks(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/recordings/rec" {
+			t.Errorf("path = %s, want the recording", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"id": "rec",
+			"status": "finished",
+			"tracks": [
+				{"s3Key": "domain/rec/p1-cam-audio.webm", "type": "audio", "size": 1024, "participantId": "p1"},
+				{"s3Key": "domain/rec/p1-cam-video.webm", "type": "video", "size": 4096, "participantId": "p1"},
+				{"s3Key": "domain/rec/p2-cam-audio.webm", "type": "audio", "size": 2048, "participantId": "p2"}
+			]
+		}`))
+	})
+
+	tracks, err := c.GetRecordingTracks(context.Background(), "rec")
+	if err != nil {
+		t.Fatalf("GetRecordingTracks: %v", err)
+	}
+	want := []RecordingTrack{
+		{S3Key: "domain/rec/p1-cam-audio.webm", Type: "audio", Size: 1024, ParticipantID: "p1"},
+		{S3Key: "domain/rec/p1-cam-video.webm", Type: "video", Size: 4096, ParticipantID: "p1"},
+		{S3Key: "domain/rec/p2-cam-audio.webm", Type: "audio", Size: 2048, ParticipantID: "p2"},
+	}
+	if !reflect.DeepEqual(tracks, want) {
+		t.Errorf("tracks = %+v, want %+v", tracks, want)
+	}
+}
+
+func TestGetMeetingSession(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/meetings/session-1" {
+			t.Errorf("path = %s, want the meeting session", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"id": "session-1",
+			"room": "standup",
+			"start_time": 1600000000,
+			"duration": 1800,
+			"ongoing": false,
+			"max_participants": 2,
+			"participants": [
+				{"user_id": "ada", "participant_id": "p1", "user_name": "Ada", "join_time": 1600000000, "duration": 1800},
+				{"user_id": null, "participant_id": "p2", "user_name": "Guest", "join_time": 1600000600, "duration": 600}
+			]
+		}`))
+	})
+
+	got, err := c.GetMeetingSession(context.Background(), "session-1")
+	if err != nil {
+		t.Fatalf("GetMeetingSession: %v", err)
+	}
+	want := &MeetingSession{
+		ID:              "session-1",
+		Room:            "standup",
+		StartTime:       1600000000,
+		Duration:        1800,
+		MaxParticipants: 2,
+		Participants: []MeetingParticipant{
+			{UserID: "ada", ParticipantID: "p1", UserName: "Ada", JoinTime: 1600000000, Duration: 1800},
+			{ParticipantID: "p2", UserName: "Guest", JoinTime: 1600000600, Duration: 600},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMeetingSession = %+v, want %+v", got, want)
+	}
+	if left := got.Participants[1].Left(); !left.Equal(time.Unix(1600001200, 0)) {
+		t.Errorf("guest left at %s, want %s", left, time.Unix(1600001200, 0))
+	}
+}
+
+func TestGetRoomUsage(t *testing.T) {
+	var sessions []MeetingSession
+	for i := 0; i < 120; i++ {
+		sessions = append(sessions, MeetingSession{
+			ID:       "s" + strconv.Itoa(i),
+			Duration: 600,
+			Participants: []MeetingParticipant{
+				{ParticipantID: "a", Duration: 600},
+				{ParticipantID: "b", Duration: 300},
+			},
+		})
+	}
+	var queries []url.Values
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		queries = append(queries, q)
+		from, to := pageBounds(len(sessions), func(i int) string { return sessions[i].ID }, q)
+		json.NewEncoder(w).Encode(GetMeetingsResponse{TotalCount: len(sessions), Meetings: sessions[from:to]})
+	})
+
+	start, end := time.Unix(1600000000, 0), time.Unix(1600086400, 0)
+	usage, err := c.GetRoomUsage(context.Background(), "standup", UsageParams{Start: start, End: end})
+	if err != nil {
+		t.Fatalf("GetRoomUsage: %v", err)
+	}
+	if want := (&UsageResponse{Sessions: 120, TotalMinutes: 1200, ParticipantMinutes: 1800}); !reflect.DeepEqual(usage, want) {
+		t.Errorf("usage = %+v, want %+v", usage, want)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("got %d page requests, want 2", len(queries))
+	}
+	for _, q := range queries {
+		if q.Get("room") != "standup" || q.Get("timeframe_start") != "1600000000" || q.Get("timeframe_end") != "1600086400" {
+			t.Errorf("query = %s, want the room and timeframe", q.Encode())
+		}
+	}
+
+	queries = nil
+	if _, err := c.GetRoomUsage(context.Background(), "standup", UsageParams{}); err != nil {
+		t.Fatalf("GetRoomUsage without a timeframe: %v", err)
+	}
+	if q := queries[0]; q.Get("timeframe_start") != "" || q.Get("timeframe_end") != "" {
+		t.Errorf("query = %s, want no timeframe for zero times", q.Encode())
+	}
+}
+
+func TestCloseVariants(t *testing.T) {
+	custom := New()
+	custom.HTTPClient = staticClient{[]byte(`{}`)}
+	base := New(WithTokenCache(10, time.Minute))
+	clients := map[string]*Client{
+		"default":         New(),
+		"custom client":   custom,
+		"with transport":  New(WithTransport(&http.Transport{})),
+		"WithToken copy":  base.WithToken("other"),
+		"with tokencache": base,
+	}
+	for name, c := range clients {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				if err := c.Close(); err != nil {
+					t.Errorf("Close #%d: %v", i+1, err)
+				}
+			}
+		})
+	}
+}
+
+// roomEchoServer answers room creates and updates with the config sent, and
+// records the properties of each request.
+func roomEchoServer(t *testing.T, sent *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Properties json.RawMessage `json:"properties"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		*sent = append(*sent, string(req.Properties))
+		fmt.Fprintf(w, `{"name":"room","config":%s}`, req.Properties)
+	}
+}
+
+// testRoomConfigRoundTrip checks cfg is sent as wantJSON by both CreateRoom
+// and UpdateRoom, and decodes back from the response unchanged.
+func testRoomConfigRoundTrip(t *testing.T, cfg *RoomConfig, wantJSON string) {
+	t.Helper()
+	var sent []string
+	c := newTestClient(t, roomEchoServer(t, &sent))
+	ctx := context.Background()
+
+	created, err := c.CreateRoom(ctx, &CreateRoomRequest{Name: String("room"), Config: cfg})
+	if err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	updated, err := c.UpdateRoom(ctx, "room", &UpdateRoomRequest{Config: cfg})
+	if err != nil {
+		t.Fatalf("UpdateRoom: %v", err)
+	}
+	if want := []string{wantJSON, wantJSON}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent properties %q, want %q", sent, want)
+	}
+	if !reflect.DeepEqual(created.Config, cfg) {
+		t.Errorf("CreateRoom config = %+v, want %+v", created.Config, cfg)
+	}
+	if !reflect.DeepEqual(updated.Config, cfg) {
+		t.Errorf("UpdateRoom config = %+v, want %+v", updated.Config, cfg)
+	}
+}
+
+func TestRoomConfigUIFields(t *testing.T) {
+	testRoomConfigRoundTrip(t, &RoomConfig{
+		EnablePrejoinUI:      False(),
+		EnableNetworkUI:      True(),
+		EnablePeopleUI:       False(),
+		EnableEmojiReactions: True(),
+		OptimizeLargeCalls:   True(),
+		Permissions:          &Permissions{CanSend: PermissionsList(Audio), HasPresence: True()},
+	}, `{"enable_prejoin_ui":false,"enable_network_ui":true,"enable_people_ui":false,"enable_emoji_reactions":true,`+
+		`"experimental_optimize_large_calls":true,"permissions":{"canSend":["audio"],"hasPresence":true}}`)
+}
+
+func TestRoomConfigEnableAdvancedChat(t *testing.T) {
+	testRoomConfigRoundTrip(t, &RoomConfig{EnableChat: True(), EnableAdvancedChat: True()},
+		`{"enable_chat":true,"enable_advanced_chat":true}`)
+}
+
+func TestRecordingsBucket(t *testing.T) {
+	bucket := &RecordingsBucket{
+		BucketName:     String("recs"),
+		BucketRegion:   String("us-west-2"),
+		AssumeRoleARN:  String("arn:aws:iam::123456789012:role/daily"),
+		AllowAPIAccess: True(),
+	}
+	const bucketJSON = `{"bucket_name":"recs","bucket_region":"us-west-2","assume_role_arn":"arn:aws:iam::123456789012:role/daily","allow_api_access":true}`
+
+	t.Run("room", func(t *testing.T) {
+		testRoomConfigRoundTrip(t, &RoomConfig{RecordingsBucket: bucket}, `{"recordings_bucket":`+bucketJSON+`}`)
+	})
+	t.Run("domain", func(t *testing.T) {
+		var sent string
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" {
+				var req struct {
+					Properties json.RawMessage `json:"properties"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+				sent = string(req.Properties)
+			}
+			w.Write([]byte(`{"domain_name":"example","config":{"recordings_bucket":` + bucketJSON + `}}`))
+		})
+
+		cfg, err := c.SetDomainConfig(context.Background(), &Config{RecordingsBucket: bucket})
+		if err != nil {
+			t.Fatalf("SetDomainConfig: %v", err)
+		}
+		if want := `{"recordings_bucket":` + bucketJSON + `}`; sent != want {
+			t.Errorf("sent %s, want %s", sent, want)
+		}
+		if !reflect.DeepEqual(cfg.Config.RecordingsBucket, bucket) {
+			t.Errorf("RecordingsBucket = %+v, want %+v", cfg.Config.RecordingsBucket, bucket)
+		}
+	})
+}
+
+func TestRoomConfigEnableHandRaising(t *testing.T) {
+	testRoomConfigRoundTrip(t, &RoomConfig{EnableHandRaising: True()}, `{"enable_hand_raising":true}`)
+	testRoomConfigRoundTrip(t, &RoomConfig{EnableHandRaising: False()}, `{"enable_hand_raising":false}`)
+}
+
+func TestRoomConfigEnableBreakoutRooms(t *testing.T) {
+	testRoomConfigRoundTrip(t, &RoomConfig{EnableBreakoutRooms: True()}, `{"enable_breakout_rooms":true}`)
+}
+
+// testTokenRoundTrip checks token is sent as wantJSON by CreateMeetingToken,
+// and that GetMeetingToken decodes the same properties back.
+func testTokenRoundTrip(t *testing.T, token *MeetingToken, wantJSON string) {
+	t.Helper()
+	var sent string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(sent))
+			return
+		}
+		var req struct {
+			Properties json.RawMessage `json:"properties"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		sent = string(req.Properties)
+		w.Write([]byte(`{"token":"abc"}`))
+	})
+	ctx := context.Background()
+
+	if _, err := c.CreateMeetingToken(ctx, &CreateMeetingTokenRequest{Properties: token}); err != nil {
+		t.Fatalf("CreateMeetingToken: %v", err)
+	}
+	if sent != wantJSON {
+		t.Errorf("sent properties %s, want %s", sent, wantJSON)
+	}
+	got, err := c.GetMeetingToken(ctx, "abc")
+	if err != nil {
+		t.Fatalf("GetMeetingToken: %v", err)
+	}
+	if !reflect.DeepEqual((*MeetingToken)(got), token) {
+		t.Errorf("GetMeetingToken = %+v, want %+v", got, token)
+	}
+}
+
+func TestMeetingTokenUIToggles(t *testing.T) {
+	testTokenRoundTrip(t, &MeetingToken{
+		RoomName:                String("room"),
+		EnablePeopleUI:          False(),
+		EnableNetworkUI:         True(),
+		EnableVideoProcessingUI: False(),
+		EnableRecordingUI:       True(),
+	}, `{"room_name":"room","enable_people_ui":false,"enable_network_ui":true,"enable_video_processing_ui":false,"enable_recording_ui":true}`)
+}
+
+func TestMeetingTokenEnablePrejoinUI(t *testing.T) {
+	testTokenRoundTrip(t, &MeetingToken{RoomName: String("room"), EnablePrejoinUI: False(), EnableNetworkUI: False()},
+		`{"room_name":"room","enable_prejoin_ui":false,"enable_network_ui":false}`)
+}
+
+func TestMeetingTokenStartCloudRecording(t *testing.T) {
+	t.Run("flag only", func(t *testing.T) {
+		testTokenRoundTrip(t, &MeetingToken{RoomName: String("room"), StartCloudRecording: True()},
+			`{"room_name":"room","start_cloud_recording":true}`)
+	})
+	t.Run("with options", func(t *testing.T) {
+		testTokenRoundTrip(t, &MeetingToken{
+			RoomName:            String("room"),
+			StartCloudRecording: True(),
+			StartCloudRecordingOpts: &CloudRecordingOptions{
+				Height:     720,
+				Width:      1280,
+				Layout:     &Layout{Preset: ActiveParticipantLayout},
+				InstanceID: String("c3df927c-f738-4471-a2b7-066fa7e95a6b"),
+			},
+		}, `{"room_name":"room","start_cloud_recording":true,"start_cloud_recording_opts":`+
+			`{"height":720,"width":1280,"layout":{"preset":"active-participant"},"instanceId":"c3df927c-f738-4471-a2b7-066fa7e95a6b"}}`)
+	})
+}
@@ -0,0 +1,103 @@
+package daily
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	defaultMu     sync.RWMutex
+	defaultClient *Client
+)
+
+// Configure builds the package-level client used by Default and the
+// package-level functions such as CreateRoom. It is safe to call concurrently
+// and calling it again replaces the client, closing the previous one.
+func Configure(opts ...Option) {
+	c := New(opts...)
+	defaultMu.Lock()
+	old := defaultClient
+	defaultClient = c
+	defaultMu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+}
+
+// Default returns the package-level client, or nil if Configure hasn't been
+// called.
+func Default() *Client {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultClient
+}
+
+func configured() (*Client, error) {
+	if c := Default(); c != nil {
+		return c, nil
+	}
+	return nil, Error{Message: ErrNotConfigured}
+}
+
+// ListRooms calls ListRooms on the default client.
+func ListRooms(ctx context.Context, req *ListRoomsRequest) (*ListRoomsResponse, error) {
+	c, err := configured()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListRooms(ctx, req)
+}
+
+// CreateRoom calls CreateRoom on the default client.
+func CreateRoom(ctx context.Context, req *CreateRoomRequest) (*CreateRoomResponse, error) {
+	c, err := configured()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateRoom(ctx, req)
+}
+
+// GetRoom calls GetRoom on the default client.
+func GetRoom(ctx context.Context, name string) (*GetRoomResponse, error) {
+	c, err := configured()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetRoom(ctx, name)
+}
+
+// UpdateRoom calls UpdateRoom on the default client.
+func UpdateRoom(ctx context.Context, name string, req *UpdateRoomRequest) (*UpdateRoomResponse, error) {
+	c, err := configured()
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateRoom(ctx, name, req)
+}
+
+// DeleteRoom calls DeleteRoom on the default client.
+func DeleteRoom(ctx context.Context, name string) error {
+	c, err := configured()
+	if err != nil {
+		return err
+	}
+	return c.DeleteRoom(ctx, name)
+}
+
+// CreateMeetingToken calls CreateMeetingToken on the default client.
+func CreateMeetingToken(ctx context.Context, req *CreateMeetingTokenRequest) (*CreateMeetingTokenResponse, error) {
+	c, err := configured()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateMeetingToken(ctx, req)
+}
+
+// GetMeetingToken calls GetMeetingToken on the default client.
+func GetMeetingToken(ctx context.Context, token string) (*GetMeetingTokenResponse, error) {
+	c, err := configured()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetMeetingToken(ctx, token)
+}
@@ -0,0 +1,36 @@
+package daily
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConfigureClosesPreviousClient(t *testing.T) {
+	defer func() {
+		defaultMu.Lock()
+		defaultClient = nil
+		defaultMu.Unlock()
+	}()
+
+	Configure(WithTokenCache(10, time.Minute))
+	first := Default()
+	Configure(WithTokenCache(10, time.Minute))
+
+	if Default() == first {
+		t.Fatal("Configure didn't replace the default client")
+	}
+	select {
+	case <-first.tokenCache.done:
+	default:
+		t.Error("previous client's token cache janitor is still running")
+	}
+	Default().Close()
+}
+
+func TestPackageFuncsRequireConfigure(t *testing.T) {
+	_, err := CreateRoom(context.Background(), &CreateRoomRequest{})
+	if e, ok := err.(Error); !ok || e.Message != ErrNotConfigured {
+		t.Fatalf("err = %v, want ErrNotConfigured", err)
+	}
+}
@@ -0,0 +1,71 @@
+package daily
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultDomainConfigTTL = 5 * time.Minute
+
+// WithDomainConfigTTL sets how long CachedDomainConfig reuses a fetched domain
+// config. The default is 5 minutes.
+func WithDomainConfigTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.domainCache.ttl = ttl
+	}
+}
+
+// CachedDomainConfig returns the domain config, only calling GetDomainConfig
+// when the cached copy is older than the configured TTL. SetDomainConfig
+// updates the cache.
+func (c *Client) CachedDomainConfig(ctx context.Context) (*DomainConfig, error) {
+	if cfg, ok := c.domainCache.get(c.now()); ok {
+		return cfg, nil
+	}
+	return c.RefreshDomainConfig(ctx)
+}
+
+// RefreshDomainConfig fetches the domain config, bypassing and then updating
+// the cache used by CachedDomainConfig.
+func (c *Client) RefreshDomainConfig(ctx context.Context) (*DomainConfig, error) {
+	cfg, err := c.GetDomainConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.domainCache.set(cfg, c.now())
+	return cfg, nil
+}
+
+type domainConfigCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	cfg       *DomainConfig
+	fetchedAt time.Time
+}
+
+func newDomainConfigCache() *domainConfigCache {
+	return &domainConfigCache{ttl: defaultDomainConfigTTL}
+}
+
+func (dc *domainConfigCache) get(now time.Time) (*DomainConfig, bool) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.cfg == nil || now.Sub(dc.fetchedAt) >= dc.ttl {
+		return nil, false
+	}
+	return dc.cfg, true
+}
+
+func (dc *domainConfigCache) set(cfg *DomainConfig, now time.Time) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.cfg = cfg
+	dc.fetchedAt = now
+}
+
+func (dc *domainConfigCache) invalidate() {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.cfg = nil
+}
@@ -0,0 +1,161 @@
+package daily
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachedDomainConfig(t *testing.T) {
+	clock := newTestClock()
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"domain_name":"example"}`))
+	}, WithClock(clock.Now), WithDomainConfigTTL(time.Minute))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		cfg, err := c.CachedDomainConfig(ctx)
+		if err != nil {
+			t.Fatalf("CachedDomainConfig: %v", err)
+		}
+		if cfg.DomainName == nil || *cfg.DomainName != "example" {
+			t.Fatalf("DomainName = %v, want example", cfg.DomainName)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("got %d calls within the TTL, want 1", calls)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := c.CachedDomainConfig(ctx); err != nil {
+		t.Fatalf("CachedDomainConfig: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("got %d calls after the TTL, want 2", calls)
+	}
+
+	if _, err := c.RefreshDomainConfig(ctx); err != nil {
+		t.Fatalf("RefreshDomainConfig: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("got %d calls after refreshing, want 3", calls)
+	}
+	if _, err := c.CachedDomainConfig(ctx); err != nil {
+		t.Fatalf("CachedDomainConfig: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls after using the refreshed config, want 3", calls)
+	}
+}
+
+func TestSetDomainConfigRefetches(t *testing.T) {
+	var methods []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Method == "POST" {
+			// Daily echoes back only the fields that were sent.
+			w.Write([]byte(`{"domain_name":"example","config":{"lang":"de"}}`))
+			return
+		}
+		w.Write([]byte(`{"domain_name":"example","config":{"lang":"de","hide_daily_branding":true,"enable_recording":"cloud"}}`))
+	})
+
+	cfg, err := c.SetDomainConfig(context.Background(), &Config{Lang: String("de")})
+	if err != nil {
+		t.Fatalf("SetDomainConfig: %v", err)
+	}
+	if got := strings.Join(methods, ","); got != "POST,GET" {
+		t.Fatalf("requests = %s, want POST,GET", got)
+	}
+	if cfg.Config == nil || cfg.Config.HideDailyBranding == nil || !*cfg.Config.HideDailyBranding {
+		t.Errorf("HideDailyBranding not populated from the refetched config: %+v", cfg.Config)
+	}
+	if cfg.Config.EnableRecording == nil || *cfg.Config.EnableRecording != "cloud" {
+		t.Errorf("EnableRecording = %v, want cloud", cfg.Config.EnableRecording)
+	}
+}
+
+func TestSetDomainConfigSendsProperties(t *testing.T) {
+	var body map[string]json.RawMessage
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding body: %v", err)
+			}
+		}
+		w.Write([]byte(`{"domain_name":"example"}`))
+	})
+
+	if _, err := c.SetDomainConfig(context.Background(), &Config{Lang: String("de")}); err != nil {
+		t.Fatalf("SetDomainConfig: %v", err)
+	}
+	if len(body) != 1 || string(body["properties"]) != `{"lang":"de"}` {
+		t.Errorf("POST body = %v, want the config under properties", body)
+	}
+}
+
+func TestGetRoomConfigDefaults(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"domain_name": "example",
+			"config": {
+				"hide_daily_branding": true,
+				"hipaa": false,
+				"lang": "fr",
+				"enable_recording": "cloud",
+				"recordings_bucket": {"bucket_name": "recs", "bucket_region": "eu-west-1"}
+			}
+		}`))
+	})
+
+	got, err := c.GetRoomConfigDefaults(context.Background())
+	if err != nil {
+		t.Fatalf("GetRoomConfigDefaults: %v", err)
+	}
+	want := &RoomConfig{
+		Lang:             String("fr"),
+		EnableRecording:  String("cloud"),
+		RecordingsBucket: &RecordingsBucket{BucketName: String("recs"), BucketRegion: String("eu-west-1")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetRoomConfigDefaults = %+v, want %+v", got, want)
+	}
+}
+
+func TestDomainRecordingDefaults(t *testing.T) {
+	const configJSON = `{"enable_recording":"cloud","enable_transcription":true,"recordings_template":"{domain_name}/{room_name}/{epoch_time}"}`
+	var sent json.RawMessage
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			var req struct {
+				Properties json.RawMessage `json:"properties"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			sent = req.Properties
+		}
+		w.Write([]byte(`{"domain_name":"example","config":` + configJSON + `}`))
+	})
+	want := &Config{
+		EnableRecording:     String(RecordingCloud),
+		EnableTranscription: True(),
+		RecordingsTemplate:  String("{domain_name}/{room_name}/{epoch_time}"),
+	}
+
+	cfg, err := c.SetDomainConfig(context.Background(), want)
+	if err != nil {
+		t.Fatalf("SetDomainConfig: %v", err)
+	}
+	if string(sent) != configJSON {
+		t.Errorf("sent %s, want %s", sent, configJSON)
+	}
+	if !reflect.DeepEqual(cfg.Config, want) {
+		t.Errorf("Config = %+v, want %+v", cfg.Config, want)
+	}
+}
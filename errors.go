@@ -1,17 +1,35 @@
 package daily
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
 
 var (
 	// HTTP Errors.
 	ErrBadRequest      = "bad request"
 	ErrUnauthorized    = "unauthorized"
+	ErrPaymentRequired = "payment required" // Feature not included in the domain's plan
+	ErrForbidden       = "forbidden"
+	ErrNotFound        = "not found"
+	ErrConflict        = "conflict"
 	ErrTooManyRequests = "too many requests"
 	ErrInternal        = "internal error"
+	ErrUpstreamGateway = "upstream gateway error" // Non-JSON server error, e.g. from a CDN
 	ErrUnexpected      = "unexpected error"
 
 	// Other errors.
-	ErrParseError = "json parse error"
+	ErrParseError         = "json parse error"
+	ErrUnknownField       = "unknown field in response" // Only with WithStrictDecoding
+	ErrResponseTooLarge   = "response too large"
+	ErrValidation         = "validation error"
+	ErrInvalidTimeWindow  = "invalid time window" // nbf not before exp
+	ErrPreconditionFailed = "precondition failed"
+	ErrDryRun             = "dry run" // Returned for every call made in dry-run mode
+	ErrNotConfigured      = "default client not configured, call Configure first"
+	ErrRecordingNotReady  = "recording not ready"
+	ErrEmptyToken         = "response contained no token"
 )
 
 // Error represents error information related to an API call.
@@ -30,6 +48,15 @@ func (e Error) Error() string {
 	}
 }
 
+// Info returns Daily's explanation of the error, such as which feature the
+// plan lacks for ErrPaymentRequired, falling back to the raw response body.
+func (e Error) Info() string {
+	if e.Details != nil && e.Details.ErrorInfo != "" {
+		return e.Details.ErrorInfo
+	}
+	return e.RawDetails
+}
+
 // ErrorDetails is the daily API error response.
 type ErrorDetails struct {
 	ErrorCode string `json:"error"`
@@ -39,3 +66,36 @@ type ErrorDetails struct {
 func (ed ErrorDetails) String() string {
 	return fmt.Sprintf("code: %s, info: %s", ed.ErrorCode, ed.ErrorInfo)
 }
+
+// isRoomExists reports whether err is Daily rejecting a room name which is
+// already taken. Besides ErrConflict, Daily has reported this as a bad request
+// saying the room already exists; other bad requests, such as an invalid
+// config, don't count.
+func isRoomExists(err error) bool {
+	e, ok := err.(Error)
+	if !ok {
+		return false
+	}
+	if e.Message == ErrConflict {
+		return true
+	}
+	return e.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(e.Info()), "already exists")
+}
+
+// isRecordingNotReady reports whether e looks like Daily refusing to link a
+// recording which is still being processed, as opposed to a hard failure.
+func isRecordingNotReady(e Error) bool {
+	if e.Message == ErrRecordingNotReady {
+		return true
+	}
+	if e.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	info := strings.ToLower(e.Info())
+	for _, s := range []string{"in-progress", "in progress", "not finished", "not ready", "processing"} {
+		if strings.Contains(info, s) {
+			return true
+		}
+	}
+	return false
+}
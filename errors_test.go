@@ -0,0 +1,101 @@
+package daily
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestForbidden(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":"authorization-error","info":"token lacks access to this room"}`))
+	})
+
+	_, err := c.GetRoom(context.Background(), "room")
+	e, ok := err.(Error)
+	if !ok || e.Message != ErrForbidden || e.StatusCode != http.StatusForbidden {
+		t.Fatalf("err = %v, want ErrForbidden", err)
+	}
+	if e.Details == nil || e.Details.ErrorCode != "authorization-error" {
+		t.Errorf("Details = %v, want the authorization-error body", e.Details)
+	}
+}
+
+func TestPaymentRequired(t *testing.T) {
+	const info = "Cloud recording is not included in your plan"
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+		w.Write([]byte(`{"error":"payment-required","info":"` + info + `"}`))
+	})
+
+	_, err := c.StartRecording(context.Background(), "room", nil)
+	e, ok := err.(Error)
+	if !ok || e.Message != ErrPaymentRequired || e.StatusCode != http.StatusPaymentRequired {
+		t.Fatalf("err = %v, want ErrPaymentRequired", err)
+	}
+	if e.Info() != info {
+		t.Errorf("Info() = %q, want %q", e.Info(), info)
+	}
+}
+
+func TestErrorInfoFallsBackToRawDetails(t *testing.T) {
+	e := Error{Message: ErrUpstreamGateway, RawDetails: "<html>bad gateway</html>"}
+	if e.Info() != e.RawDetails {
+		t.Errorf("Info() = %q, want RawDetails", e.Info())
+	}
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		want   string
+	}{
+		{http.StatusOK, ""},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+	} {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/" {
+					t.Errorf("path = %s, want the domain config", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"domain_name":"example"}`))
+			})
+
+			err := c.VerifyCredentials(context.Background())
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("VerifyCredentials: %v", err)
+				}
+				return
+			}
+			if e, ok := err.(Error); !ok || e.Message != tt.want {
+				t.Fatalf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpstreamGatewayError(t *testing.T) {
+	page := "<html><body>" + strings.Repeat("Service Unavailable ", 100) + "</body></html>"
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(page))
+	})
+
+	_, err := c.GetRoom(context.Background(), "room")
+	e, ok := err.(Error)
+	if !ok || e.Message != ErrUpstreamGateway || e.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want ErrUpstreamGateway", err)
+	}
+	if want := page[:maxRawDetails] + "..."; e.RawDetails != want {
+		t.Errorf("RawDetails = %q, want the first %d bytes of the page", e.RawDetails, maxRawDetails)
+	}
+	if e.Details != nil {
+		t.Errorf("Details = %v, want nil for an HTML body", e.Details)
+	}
+}
@@ -0,0 +1,39 @@
+// Demonstrates dry-run mode, printing the request that would create a room.
+// go run examples/dryrun/dryrun.go
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	daily "github.com/range-labs/daily-go"
+)
+
+func main() {
+	ctx := context.Background()
+	client := daily.New(daily.WithDryRun(func(req *http.Request) {
+		log.Println(req.Method, req.URL)
+		for k, v := range req.Header {
+			log.Println("> ", k+":", v)
+		}
+		if req.Body != nil {
+			b, _ := ioutil.ReadAll(req.Body)
+			log.Println("> ", string(b))
+		}
+	}))
+
+	newRoom := &daily.CreateRoomRequest{
+		Name: daily.String("daily-go-example-room"),
+		Config: &daily.RoomConfig{
+			ExpiresAt: daily.Timestamp(time.Now().Add(time.Hour)),
+		},
+	}
+	if _, err := client.CreateRoom(ctx, newRoom); err != nil {
+		if e, ok := err.(daily.Error); !ok || e.Message != daily.ErrDryRun {
+			log.Fatal(err)
+		}
+	}
+}
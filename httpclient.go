@@ -1,19 +1,183 @@
 package daily
 
-import "net/http"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
 
 // httpClient defines the minimal interface needed for an http.Client to be implemented.
 type httpClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// middleware wraps an httpClient with extra behavior, like an
+// http.RoundTripper wrapping another.
+type middleware func(httpClient) httpClient
+
+// Layers of the middleware chain, innermost first. Each option sets its own
+// layer, so the chain is the same whatever order the options are given in.
+// Retries are outside auth, so every attempt is authenticated.
+const (
+	authLayer = iota
+	retryLayer
+	numLayers
+)
+
+// chain wraps hc in the set layers.
+func chain(hc httpClient, layers [numLayers]middleware) httpClient {
+	for _, m := range layers {
+		if m != nil {
+			hc = m(hc)
+		}
+	}
+	return hc
+}
+
 type authClient struct {
 	httpClient
 	accessToken string
 }
 
 func (a *authClient) Do(req *http.Request) (*http.Response, error) {
-	req.Header.Add("Authorization", "Bearer "+a.accessToken)
-	req.Header.Add("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+a.accessToken)
 	return a.httpClient.Do(req)
 }
+
+// withTransport returns a copy of hc using rt, if hc is an http.Client.
+// Other clients are returned as is.
+func withTransport(hc httpClient, rt http.RoundTripper) httpClient {
+	if v, ok := hc.(*http.Client); ok {
+		cp := *v
+		cp.Transport = rt
+		return &cp
+	}
+	return hc
+}
+
+var errResponseTooLarge = errors.New("response too large")
+
+// maxBytesReader reads at most n bytes from r, failing with
+// errResponseTooLarge if r holds more.
+type maxBytesReader struct {
+	r io.Reader
+	n int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.n <= 0 {
+		var b [1]byte
+		if n, _ := m.r.Read(b[:]); n > 0 {
+			return 0, errResponseTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > m.n {
+		p = p[:m.n]
+	}
+	n, err := m.r.Read(p)
+	m.n -= int64(n)
+	return n, err
+}
+
+// prefixBuffer keeps the first n bytes written to it and discards the rest.
+type prefixBuffer struct {
+	buf []byte
+	n   int
+}
+
+func (p *prefixBuffer) Write(b []byte) (int, error) {
+	if room := p.n - len(p.buf); room > 0 {
+		if len(b) < room {
+			room = len(b)
+		}
+		p.buf = append(p.buf, b[:room]...)
+	}
+	return len(b), nil
+}
+
+// isJSON reports whether the headers declare a JSON body. A missing
+// Content-Type is assumed to be JSON.
+func isJSON(h http.Header) bool {
+	ct := h.Get("Content-Type")
+	if ct == "" {
+		return true
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	return err == nil && (mt == "application/json" || strings.HasSuffix(mt, "+json"))
+}
+
+// streamDecoder is implemented by list responses, to decode their items one
+// at a time with decodeList.
+type streamDecoder interface {
+	decodeStream(dec *json.Decoder, strict bool) error
+}
+
+// decodeList decodes a JSON object whose itemsKey field is an array into the
+// slice items points to, one element at a time. Other fields are decoded into
+// the matching entry of fields. Unknown fields are skipped, or fail if strict.
+// An empty input fails with io.EOF.
+func decodeList(dec *json.Decoder, strict bool, itemsKey string, items interface{}, fields map[string]interface{}) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("json: cannot unmarshal %v into a list response", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		switch {
+		case key == itemsKey:
+			if err := decodeItems(dec, reflect.ValueOf(items).Elem()); err != nil {
+				return err
+			}
+		case fields[key] != nil:
+			if err := dec.Decode(fields[key]); err != nil {
+				return err
+			}
+		case strict:
+			return fmt.Errorf("json: unknown field %q", key)
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// decodeItems decodes a JSON array into the slice s an element at a time.
+func decodeItems(dec *json.Decoder, s reflect.Value) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		s.Set(reflect.Zero(s.Type()))
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("json: cannot unmarshal %v into %s", tok, s.Type())
+	}
+	s.Set(reflect.MakeSlice(s.Type(), 0, 0))
+	for dec.More() {
+		s.Set(reflect.Append(s, reflect.Zero(s.Type().Elem())))
+		if err := dec.Decode(s.Index(s.Len() - 1).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
@@ -0,0 +1,134 @@
+package daily
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestChainAuthenticatesEveryAttempt(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"auth first":  {WithAuth("secret"), WithRetries(3)},
+		"retry first": {WithRetries(3), WithAuth("secret")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				auths []string
+			)
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				auths = append(auths, r.Header.Get("Authorization"))
+				attempt := len(auths)
+				mu.Unlock()
+				if attempt < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					w.Write([]byte(`{"error":"unavailable"}`))
+					return
+				}
+				w.Write([]byte(`{"name":"room"}`))
+			}, opts...)
+
+			if _, err := c.GetRoom(context.Background(), "room"); err != nil {
+				t.Fatalf("GetRoom: %v", err)
+			}
+			if len(auths) != 3 {
+				t.Fatalf("got %d attempts, want 3", len(auths))
+			}
+			for i, auth := range auths {
+				if auth != "Bearer secret" {
+					t.Errorf("attempt %d: Authorization = %q, want %q", i+1, auth, "Bearer secret")
+				}
+			}
+		})
+	}
+}
+
+// newCountingServer starts a test server answering JSON and counts the
+// connections made to it.
+func newCountingServer(tb testing.TB, conns *int32) *url.URL {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"room","config":{"max_participants":10}}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(conns, 1)
+		}
+	}
+	srv.Start()
+	tb.Cleanup(srv.Close)
+	u, _ := url.Parse(srv.URL + "/v1/")
+	return u
+}
+
+func TestConnectionReuse(t *testing.T) {
+	var conns int32
+	c := New(WithTransport(&http.Transport{MaxIdleConnsPerHost: 4}))
+	c.BaseURL = *newCountingServer(t, &conns)
+	defer c.Close()
+
+	for i := 0; i < 20; i++ {
+		if _, err := c.GetRoom(context.Background(), "room"); err != nil {
+			t.Fatalf("GetRoom: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("20 sequential calls used %d connections, want 1", n)
+	}
+}
+
+func BenchmarkConnectionReuse(b *testing.B) {
+	var conns int32
+	c := New(WithTransport(&http.Transport{MaxIdleConnsPerHost: 16}))
+	c.BaseURL = *newCountingServer(b, &conns)
+	defer c.Close()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.GetRoom(context.Background(), "room"); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+	b.ReportMetric(float64(atomic.LoadInt32(&conns)), "conns")
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	big := `{"name":"room","info":"` + strings.Repeat("x", 200) + `"}`
+	for name, status := range map[string]int{"success": http.StatusOK, "error": http.StatusBadRequest} {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+				w.Write([]byte(big))
+			}, WithMaxResponseBytes(100))
+
+			_, err := c.GetRoom(context.Background(), "room")
+			if e, ok := err.(Error); !ok || e.Message != ErrResponseTooLarge || e.StatusCode != status {
+				t.Fatalf("GetRoom err = %v, want ErrResponseTooLarge with status %d", err, status)
+			}
+		})
+	}
+}
+
+func TestMaxResponseBytesDefault(t *testing.T) {
+	for _, n := range []int64{0, -1} {
+		if c := New(WithMaxResponseBytes(n)); c.maxResponseBytes != defaultMaxResponseBytes {
+			t.Errorf("WithMaxResponseBytes(%d) limit = %d, want the default %d", n, c.maxResponseBytes, defaultMaxResponseBytes)
+		}
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"room"}`))
+	}, WithMaxResponseBytes(int64(len(`{"name":"room"}`))))
+	if _, err := c.GetRoom(context.Background(), "room"); err != nil {
+		t.Errorf("GetRoom with a body exactly at the limit: %v", err)
+	}
+}
@@ -1,6 +1,14 @@
 package daily
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"time"
+)
 
 // DomainConfig is used when getting and setting the domain configuration.
 // https://docs.daily.co/reference#get-domain-configuration
@@ -17,6 +25,35 @@ type Config struct {
 	HIPPAA                *bool   `json:"hipaa,omitempty"`
 	IntercomAutoRecord    *bool   `json:"intercom_auto_record,omitempty"`
 	Lang                  *string `json:"lang,omitempty"`
+
+	// Recording defaults.
+	EnableRecording     *string           `json:"enable_recording,omitempty"` // Default recording type for rooms
+	EnableTranscription *bool             `json:"enable_transcription,omitempty"`
+	RecordingsTemplate  *string           `json:"recordings_template,omitempty"` // Path template for stored recordings
+	RecordingsBucket    *RecordingsBucket `json:"recordings_bucket,omitempty"`
+}
+
+// RecordingsBucket is a customer-owned S3 bucket cloud recordings are stored in.
+// https://docs.daily.co/guides/products/live-streaming-recording/storing-recordings-in-a-custom-s3-bucket
+type RecordingsBucket struct {
+	BucketName     *string `json:"bucket_name,omitempty"`
+	BucketRegion   *string `json:"bucket_region,omitempty"`
+	AssumeRoleARN  *string `json:"assume_role_arn,omitempty"`
+	AllowAPIAccess *bool   `json:"allow_api_access,omitempty"`
+}
+
+// RoomDefaults maps the domain settings which also apply to rooms (Lang,
+// EnableRecording and RecordingsBucket) onto a RoomConfig. A nil Config yields
+// an empty RoomConfig.
+func (c *Config) RoomDefaults() *RoomConfig {
+	rc := &RoomConfig{}
+	if c == nil {
+		return rc
+	}
+	rc.Lang = c.Lang
+	rc.EnableRecording = c.EnableRecording
+	rc.RecordingsBucket = c.RecordingsBucket
+	return rc
 }
 
 // Room contains information about a video location and configuration.
@@ -31,6 +68,114 @@ type Room struct {
 	Config     *RoomConfig `json:"config"`
 }
 
+// ExportConfig serializes the room as the JSON of a CreateRoomRequest which
+// recreates it, leaving out server-assigned fields such as ID, URL and
+// CreatedAt. See ImportRoomConfig.
+func (r *Room) ExportConfig() ([]byte, error) {
+	return json.MarshalIndent(&CreateRoomRequest{
+		Name:    String(r.Name),
+		Privacy: r.Privacy,
+		Config:  r.Config,
+	}, "", "  ")
+}
+
+// ImportRoomConfig parses a room exported with Room.ExportConfig.
+func ImportRoomConfig(data []byte) (*CreateRoomRequest, error) {
+	req := &CreateRoomRequest{}
+	if err := json.Unmarshal(data, req); err != nil {
+		return nil, Error{Message: ErrParseError + ": " + err.Error()}
+	}
+	return req, nil
+}
+
+// RoomTemplate is a room configuration shared by many rooms, defined once and
+// passed to Client.CreateRoomFromTemplate.
+type RoomTemplate struct {
+	Privacy RoomPrivacy
+	Config  RoomConfig
+}
+
+// RoomOption overrides part of a room created from a RoomTemplate. Config is
+// never nil, and is a copy which can be changed without affecting the template.
+type RoomOption func(*CreateRoomRequest)
+
+// RoomName sets the name of a room created from a template. Without it Daily
+// picks a random name.
+func RoomName(name string) RoomOption {
+	return func(r *CreateRoomRequest) {
+		r.Name = String(name)
+	}
+}
+
+// request returns a CreateRoomRequest holding a deep copy of the template.
+func (t *RoomTemplate) request() (*CreateRoomRequest, error) {
+	data, err := json.Marshal(&t.Config)
+	if err != nil {
+		return nil, err
+	}
+	req := &CreateRoomRequest{Privacy: t.Privacy, Config: &RoomConfig{}}
+	if err := json.Unmarshal(data, req.Config); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// ExpiresAt returns the room's expiry (exp), and false if it has none. Unlike
+// TimeUntilExpiry it tells rooms without an expiry apart from expired ones.
+func (r *Room) ExpiresAt() (time.Time, bool) {
+	if r.Config == nil || r.Config.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+	return time.Unix(*r.Config.ExpiresAt, 0), true
+}
+
+// IsExpired reports whether the room's expiry (exp) is at or before now. Rooms
+// without an expiry never expire.
+func (r *Room) IsExpired(now time.Time) bool {
+	exp, ok := r.ExpiresAt()
+	return ok && !now.Before(exp)
+}
+
+// TimeUntilExpiry returns how long until the room expires. It is zero for
+// rooms which have already expired or have no expiry.
+func (r *Room) TimeUntilExpiry(now time.Time) time.Duration {
+	exp, ok := r.ExpiresAt()
+	if !ok {
+		return 0
+	}
+	if d := exp.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// JoinabilityStatus reports whether the room can be joined at now, i.e. now is
+// between its nbf and exp. opensIn is how long until a room which isn't open
+// yet opens, and closesIn how long until an open room expires. Both are zero
+// when they don't apply, including for an unset nbf or exp.
+func (r *Room) JoinabilityStatus(now time.Time) (joinable bool, opensIn, closesIn time.Duration) {
+	if r.Config != nil && r.Config.NotBefore != nil {
+		if d := time.Unix(*r.Config.NotBefore, 0).Sub(now); d > 0 {
+			return false, d, 0
+		}
+	}
+	if r.IsExpired(now) {
+		return false, 0, 0
+	}
+	return true, 0, r.TimeUntilExpiry(now)
+}
+
+// PresenceParticipant is a participant currently in a room.
+// https://docs.daily.co/reference/rest-api/rooms/get-room-presence
+type PresenceParticipant struct {
+	ID       string    `json:"id"`
+	Room     string    `json:"room"`
+	UserID   string    `json:"userId"`
+	UserName string    `json:"userName"`
+	JoinTime time.Time `json:"joinTime"`
+	Duration int       `json:"duration"` // Seconds since joining
+}
+
 // RoomPrivacy controls who can join a meeting.
 type RoomPrivacy string
 
@@ -54,64 +199,393 @@ type Permissions struct {
 	HasPresence *bool             `json:"hasPresence,omitempty"`
 }
 
+// PermissionsBuilder builds Permissions, validating the permission types.
+//
+//	perms, err := daily.NewPermissions().AllowSend(daily.Audio, daily.Video).Build()
+type PermissionsBuilder struct {
+	canSend     *[]PermissionType
+	hasPresence *bool
+}
+
+// NewPermissions starts building Permissions.
+func NewPermissions() *PermissionsBuilder {
+	return &PermissionsBuilder{}
+}
+
+// AllowSend adds the types of media participants may send.
+func (b *PermissionsBuilder) AllowSend(types ...PermissionType) *PermissionsBuilder {
+	canSend := []PermissionType{}
+	if b.canSend != nil {
+		canSend = append(canSend, *b.canSend...)
+	}
+	canSend = append(canSend, types...)
+	b.canSend = &canSend
+	return b
+}
+
+// WithPresence sets whether participants are visible to others.
+func (b *PermissionsBuilder) WithPresence(hasPresence bool) *PermissionsBuilder {
+	b.hasPresence = Bool(hasPresence)
+	return b
+}
+
+// Build returns the Permissions, or an error if an unknown permission type was
+// allowed.
+func (b *PermissionsBuilder) Build() (*Permissions, error) {
+	if b.canSend != nil {
+		for _, t := range *b.canSend {
+			switch t {
+			case Video, Audio, ScreenAudio, ScreenVideo:
+			default:
+				return nil, Error{Message: ErrValidation + ": unknown permission type " + string(t)}
+			}
+		}
+	}
+	return &Permissions{CanSend: b.canSend, HasPresence: b.hasPresence}, nil
+}
+
+// SignalingType is the signaling implementation used by a room.
+type SignalingType string
+
+const (
+	SignalingWS         SignalingType = "ws"
+	SignalingPeerToPeer SignalingType = "peer-to-peer"
+	SignalingSFU        SignalingType = "sfu"
+)
+
 // RoomConfig is the configuration for a room.
 type RoomConfig struct {
-	NotBefore                *int64  `json:"nbf,omitempty"` // Unix timestamp in seconds
-	ExpiresAt                *int64  `json:"exp,omitempty"` // Unix timestamp in seconds
-	StartVideoOff            *bool   `json:"start_video_off,omitempty"`
-	StartAudioOff            *bool   `json:"start_audio_off,omitempty"`
-	MaxParticipants          *int32  `json:"max_participants,omitempty"`
-	AutoJoin                 *bool   `json:"autojoin,omitempty"`
-	EnableKnocking           *bool   `json:"enable_knocking,omitempty"`
-	EnableScreenShare        *bool   `json:"enable_screenshare,omitempty"`
-	EnableChat               *bool   `json:"enable_chat,omitempty"`
-	OwnerOnlyBroadcast       *bool   `json:"owner_only_broadcast,omitempty"`
-	EnableRecording          *string `json:"enable_recording,omitempty"`
-	EjectAtRoomExpiry        *bool   `json:"eject_at_room_exp,omitempty"`
-	EjectAfterElapsed        *int32  `json:"eject_after_elapsed,omitempty"`
-	Lang                     *string `json:"lang,omitempty"`
-	MeetingJoinHook          *string `json:"meeting_join_hook,omitempty"`
-	SignalingType            *string `json:"signaling_impl,omitempty"` // In JSON, they spell it 'signaling' so we use that
-	SFUSwitchover            *int32  `json:"sfu_switchover,omitempty"`
-	EnableMeshSFU            *bool   `json:"enable_mesh_sfu,omitempty"`
-	EnableTerseLogging       *bool   `json:"enable_terse_logging,omitempty"`
-	EnableHiddenParticipants *bool   `json:"enable_hidden_participants,omitempty"`
+	NotBefore                *int64         `json:"nbf,omitempty"` // Unix timestamp in seconds
+	ExpiresAt                *int64         `json:"exp,omitempty"` // Unix timestamp in seconds
+	StartVideoOff            *bool          `json:"start_video_off,omitempty"`
+	StartAudioOff            *bool          `json:"start_audio_off,omitempty"`
+	MaxParticipants          *int32         `json:"max_participants,omitempty"`
+	AutoJoin                 *bool          `json:"autojoin,omitempty"`
+	EnableKnocking           *bool          `json:"enable_knocking,omitempty"`
+	EnableScreenShare        *bool          `json:"enable_screenshare,omitempty"`
+	EnableChat               *bool          `json:"enable_chat,omitempty"`
+	EnableAdvancedChat       *bool          `json:"enable_advanced_chat,omitempty"` // Requires EnableChat
+	OwnerOnlyBroadcast       *bool          `json:"owner_only_broadcast,omitempty"`
+	EnableRecording          *string        `json:"enable_recording,omitempty"`
+	EjectAtRoomExpiry        *bool          `json:"eject_at_room_exp,omitempty"`
+	EjectAfterElapsed        *int32         `json:"eject_after_elapsed,omitempty"` // Seconds, see EjectAfter
+	Lang                     *string        `json:"lang,omitempty"`
+	MeetingJoinHook          *string        `json:"meeting_join_hook,omitempty"`
+	SignalingType            *SignalingType `json:"signaling_impl,omitempty"` // In JSON, they spell it 'signaling' so we use that
+	SFUSwitchover            *int32         `json:"sfu_switchover,omitempty"` // Participant count, not a duration
+	EnableMeshSFU            *bool          `json:"enable_mesh_sfu,omitempty"`
+	EnableTerseLogging       *bool          `json:"enable_terse_logging,omitempty"`
+	EnableHiddenParticipants *bool          `json:"enable_hidden_participants,omitempty"`
+	Geo                      *string        `json:"geo,omitempty"` // One of the Geo* regions
+	EnablePrejoinUI          *bool          `json:"enable_prejoin_ui,omitempty"`
+	EnableNetworkUI          *bool          `json:"enable_network_ui,omitempty"`
+	EnablePeopleUI           *bool          `json:"enable_people_ui,omitempty"`
+	EnableEmojiReactions     *bool          `json:"enable_emoji_reactions,omitempty"`
+	EnableHandRaising        *bool          `json:"enable_hand_raising,omitempty"`
+	EnableBreakoutRooms      *bool          `json:"enable_breakout_rooms,omitempty"`
+	OptimizeLargeCalls       *bool          `json:"experimental_optimize_large_calls,omitempty"`
+
+	Permissions      *Permissions      `json:"permissions,omitempty"` // Defaults for participants joining without a token
+	RecordingsBucket *RecordingsBucket `json:"recordings_bucket,omitempty"`
+}
+
+// validate checks the config for values Daily is known to reject.
+func (rc *RoomConfig) validate() error {
+	if rc == nil {
+		return nil
+	}
+	if rc.Geo != nil && !knownGeos[*rc.Geo] {
+		return Error{Message: ErrValidation + ": unknown geo " + *rc.Geo}
+	}
+	return validateTimeWindow(rc.NotBefore, rc.ExpiresAt)
+}
+
+// validateTimeWindow fails with ErrInvalidTimeWindow if both nbf and exp are set
+// and nbf is not before exp, which would make a room or token unusable.
+func validateTimeWindow(nbf, exp *int64) error {
+	if nbf != nil && exp != nil && *nbf >= *exp {
+		return Error{Message: fmt.Sprintf("%s: nbf %d is not before exp %d", ErrInvalidTimeWindow, *nbf, *exp)}
+	}
+	return nil
+}
+
+// DiffRoomConfig returns a config holding only the fields set in desired
+// whose values differ from current, for passing to UpdateRoom. Fields unset in
+// desired are left out, as UpdateRoom leaves them unchanged. Returns nil if
+// there is nothing to update.
+func DiffRoomConfig(current, desired *RoomConfig) *RoomConfig {
+	if desired == nil {
+		return nil
+	}
+	if current == nil {
+		current = &RoomConfig{}
+	}
+	cv, dv := reflect.ValueOf(current).Elem(), reflect.ValueOf(desired).Elem()
+	diff := &RoomConfig{}
+	rv := reflect.ValueOf(diff).Elem()
+	changed := false
+	for i := 0; i < dv.NumField(); i++ {
+		d := dv.Field(i)
+		if d.IsNil() || reflect.DeepEqual(cv.Field(i).Interface(), d.Interface()) {
+			continue
+		}
+		rv.Field(i).Set(d)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return diff
+}
+
+// Regions a room's SFU can be pinned to with RoomConfig.Geo.
+const (
+	GeoAFSouth1     = "af-south-1"
+	GeoAPNortheast2 = "ap-northeast-2"
+	GeoAPSoutheast1 = "ap-southeast-1"
+	GeoAPSoutheast2 = "ap-southeast-2"
+	GeoAPSouth1     = "ap-south-1"
+	GeoEUCentral1   = "eu-central-1"
+	GeoEUWest2      = "eu-west-2"
+	GeoSAEast1      = "sa-east-1"
+	GeoUSEast1      = "us-east-1"
+	GeoUSWest2      = "us-west-2"
+)
+
+// KnownRegions returns the Geo* regions, sorted. Daily has no endpoint to list
+// regions, so this is the set known to this library.
+func KnownRegions() []string {
+	regions := make([]string, 0, len(knownGeos))
+	for geo := range knownGeos {
+		regions = append(regions, geo)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+var knownGeos = map[string]bool{
+	GeoAFSouth1:     true,
+	GeoAPNortheast2: true,
+	GeoAPSoutheast1: true,
+	GeoAPSoutheast2: true,
+	GeoAPSouth1:     true,
+	GeoEUCentral1:   true,
+	GeoEUWest2:      true,
+	GeoSAEast1:      true,
+	GeoUSEast1:      true,
+	GeoUSWest2:      true,
+}
+
+// Recording types for the EnableRecording settings of rooms, tokens and domains.
+const (
+	RecordingCloud     = "cloud"
+	RecordingLocal     = "local"
+	RecordingRawTracks = "raw-tracks"
+)
+
+// ValidateRecordingConsistency returns an error if the token's recording
+// settings contradict the room's. A token's EnableRecording takes precedence
+// over the room's for its holder, so differing values are almost always a
+// mistake. Use RecordingPolicy to set both consistently.
+func ValidateRecordingConsistency(room *RoomConfig, token *MeetingToken) error {
+	if room == nil || token == nil {
+		return nil
+	}
+	roomType := ""
+	if room.EnableRecording != nil {
+		roomType = *room.EnableRecording
+	}
+	effective := roomType
+	if token.EnableRecording != nil {
+		if roomType != "" && *token.EnableRecording != roomType {
+			return Error{Message: fmt.Sprintf("%s: token enable_recording %q contradicts room's %q", ErrValidation, *token.EnableRecording, roomType)}
+		}
+		effective = *token.EnableRecording
+	}
+	if token.StartCloudRecording != nil && *token.StartCloudRecording && effective != RecordingCloud {
+		return Error{Message: fmt.Sprintf("%s: token starts a cloud recording, but recording type is %q", ErrValidation, effective)}
+	}
+	return nil
+}
+
+// RecordingPolicy sets the same recording type on a room and its tokens.
+type RecordingPolicy struct {
+	Type string // One of the Recording* types
+}
+
+// ApplyToRoom sets the room's recording type.
+func (p RecordingPolicy) ApplyToRoom(rc *RoomConfig) {
+	rc.EnableRecording = String(p.Type)
+}
+
+// ApplyToToken sets the token's recording type. Tokens which auto-start cloud
+// recording have that turned off unless the type is RecordingCloud.
+func (p RecordingPolicy) ApplyToToken(t *MeetingToken) {
+	t.EnableRecording = String(p.Type)
+	if p.Type != RecordingCloud && t.StartCloudRecording != nil {
+		t.StartCloudRecording = False()
+	}
 }
 
 // MeetingToken is the configuration that controls room access and session configuration on a per-user basis.
 type MeetingToken struct {
-	NotBefore           *int64       `json:"nbf,omitempty"` // Unix timestamp in seconds
-	ExpiresAt           *int64       `json:"exp,omitempty"` // Unix timestamp in seconds
-	RoomName            *string      `json:"room_name,omitempty"`
-	IsOwner             *bool        `json:"is_owner,omitempty"`
-	UserName            *string      `json:"user_name,omitempty"`
-	UserID              *string      `json:"user_id,omitempty"`
-	EnableScreenShare   *bool        `json:"enable_screenshare,omitempty"`
-	StartVideoOff       *bool        `json:"start_video_off,omitempty"`
-	StartAudioOff       *bool        `json:"start_audio_off,omitempty"`
-	EnableRecording     *string      `json:"enable_recording,omitempty"`
-	StartCloudRecording *bool        `json:"start_cloud_recording,omitempty"`
-	CloseTabOnExit      *bool        `json:"close_tab_on_exit,omitempty"`
-	EjectAtRoomExpiry   *bool        `json:"eject_at_room_exp,omitempty"`
-	EjectAfterElapsed   *int32       `json:"eject_after_elapsed,omitempty"`
-	Lang                *string      `json:"lang,omitempty"`
-	Permissions         *Permissions `json:"permissions,omitempty"`
+	NotBefore               *int64                 `json:"nbf,omitempty"` // Unix timestamp in seconds
+	ExpiresAt               *int64                 `json:"exp,omitempty"` // Unix timestamp in seconds
+	RoomName                *string                `json:"room_name,omitempty"`
+	IsOwner                 *bool                  `json:"is_owner,omitempty"`
+	UserName                *string                `json:"user_name,omitempty"`
+	UserID                  *string                `json:"user_id,omitempty"`
+	EnableScreenShare       *bool                  `json:"enable_screenshare,omitempty"`
+	StartVideoOff           *bool                  `json:"start_video_off,omitempty"`
+	StartAudioOff           *bool                  `json:"start_audio_off,omitempty"`
+	EnableRecording         *string                `json:"enable_recording,omitempty"`
+	StartCloudRecording     *bool                  `json:"start_cloud_recording,omitempty"`
+	StartCloudRecordingOpts *CloudRecordingOptions `json:"start_cloud_recording_opts,omitempty"` // Used with StartCloudRecording
+	CloseTabOnExit          *bool                  `json:"close_tab_on_exit,omitempty"`
+	EjectAtRoomExpiry       *bool                  `json:"eject_at_room_exp,omitempty"`
+	EjectAfterElapsed       *int32                 `json:"eject_after_elapsed,omitempty"`
+	Lang                    *string                `json:"lang,omitempty"`
+	Permissions             *Permissions           `json:"permissions,omitempty"`
+	EnablePrejoinUI         *bool                  `json:"enable_prejoin_ui,omitempty"` // false lets the holder skip the prejoin screen
+	EnablePeopleUI          *bool                  `json:"enable_people_ui,omitempty"`
+	EnableNetworkUI         *bool                  `json:"enable_network_ui,omitempty"`
+	EnableVideoProcessingUI *bool                  `json:"enable_video_processing_ui,omitempty"`
+	EnableRecordingUI       *bool                  `json:"enable_recording_ui,omitempty"`
+}
+
+// LayoutPreset selects how participants are composed in a recording.
+type LayoutPreset string
+
+const (
+	DefaultLayout           LayoutPreset = "default"
+	SingleParticipantLayout LayoutPreset = "single-participant"
+	ActiveParticipantLayout LayoutPreset = "active-participant"
+	PortraitLayout          LayoutPreset = "portrait"
+	CustomLayout            LayoutPreset = "custom"
+)
+
+// ValidateTokenAgainstRoom returns human-readable warnings about token settings
+// which conflict with the room's, such as a non-owner token allowed to send
+// media in a room where only owners may broadcast. It returns nil if there are
+// no conflicts.
+func ValidateTokenAgainstRoom(room *Room, token *MeetingToken) []string {
+	if room == nil || token == nil {
+		return nil
+	}
+	var warnings []string
+	if token.RoomName != nil && *token.RoomName != room.Name {
+		warnings = append(warnings, fmt.Sprintf("token is for room %s, not %s", *token.RoomName, room.Name))
+	}
+	cfg := room.Config
+	if cfg == nil {
+		return warnings
+	}
+	isOwner := token.IsOwner != nil && *token.IsOwner
+	if cfg.OwnerOnlyBroadcast != nil && *cfg.OwnerOnlyBroadcast && !isOwner &&
+		token.Permissions != nil && token.Permissions.CanSend != nil {
+		for _, t := range *token.Permissions.CanSend {
+			if t == Video || t == Audio {
+				warnings = append(warnings, fmt.Sprintf("room only lets owners broadcast, but non-owner token can send %s", t))
+			}
+		}
+	}
+	return warnings
+}
+
+// CloudRecordingOptions configures a recording started automatically by a
+// meeting token, like StartRecordingRequest does for StartRecording.
+type CloudRecordingOptions struct {
+	Height     int     `json:"height,omitempty"`
+	Width      int     `json:"width,omitempty"`
+	Layout     *Layout `json:"layout,omitempty"`
+	InstanceID *string `json:"instanceId,omitempty"` // Distinguishes concurrent recordings of a room
 }
 
 // Layout is a configuration for started a recording
+// https://docs.daily.co/reference/rest-api/recordings#layout
 type Layout struct {
-	Preset string `json:"preset"`
+	Preset            LayoutPreset           `json:"preset"`
+	MaxCams           *int32                 `json:"max_cam_streams,omitempty"`    // default and portrait presets
+	SessionID         *string                `json:"session_id,omitempty"`         // single-participant preset
+	ParticipantIDs    []string               `json:"participant_ids,omitempty"`    // custom preset
+	CompositionParams map[string]interface{} `json:"composition_params,omitempty"` // custom preset
+}
+
+// RecordingStatus is the processing state of a recording. Statuses unknown to
+// this library are kept as is.
+type RecordingStatus string
+
+const (
+	RecordingInProgress RecordingStatus = "in-progress"
+	RecordingFinished   RecordingStatus = "finished"
+	RecordingCanceled   RecordingStatus = "canceled"
+)
+
+// IsTerminal reports whether the recording won't change status anymore.
+func (s RecordingStatus) IsTerminal() bool {
+	return s == RecordingFinished || s == RecordingCanceled
 }
 
 type Recording struct {
-	Id              string        `json:"id"`
-	StartTs         int           `json:"start_ts"`
-	Status          string        `json:"status"`
-	MaxParticipants int           `json:"max_participants"`
-	RoomName        string        `json:"room_name"`
-	Tracks          []interface{} `json:"tracks"`
-	Duration        int           `json:"duration"`
-	ShareToken      string        `json:"share_token"`
+	Id              string           `json:"id"`
+	StartTs         int              `json:"start_ts"`
+	Status          RecordingStatus  `json:"status"`
+	MaxParticipants int              `json:"max_participants"`
+	RoomName        string           `json:"room_name"`
+	Tracks          []RecordingTrack `json:"tracks"` // Only set for raw-tracks recordings
+	Duration        int              `json:"duration"`
+	ShareToken      string           `json:"share_token"`
+}
+
+// ShareURL returns the public link for the recording, of the form
+// https://<domain>.daily.co/recordings/<share_token>, where domain is the
+// domain name as in DomainConfig.DomainName. Returns "" if the recording has
+// no share token.
+func (r *Recording) ShareURL(domain string) string {
+	if r.ShareToken == "" {
+		return ""
+	}
+	return "https://" + domain + ".daily.co/recordings/" + url.PathEscape(r.ShareToken)
+}
+
+// MeetingSession is a single session of participants meeting in a room.
+// https://docs.daily.co/reference/rest-api/meetings
+type MeetingSession struct {
+	ID              string               `json:"id"`
+	Room            string               `json:"room"`
+	StartTime       int64                `json:"start_time"` // Unix timestamp in seconds
+	Duration        int                  `json:"duration"`   // Seconds
+	Ongoing         bool                 `json:"ongoing"`
+	MaxParticipants int                  `json:"max_participants"`
+	Participants    []MeetingParticipant `json:"participants"`
+}
+
+// MeetingParticipant is a participant's attendance in a meeting session.
+type MeetingParticipant struct {
+	UserID        string `json:"user_id"`
+	ParticipantID string `json:"participant_id"`
+	UserName      string `json:"user_name"`
+	JoinTime      int64  `json:"join_time"` // Unix timestamp in seconds
+	Duration      int    `json:"duration"`  // Seconds
+}
+
+// Joined returns when the participant joined.
+func (p MeetingParticipant) Joined() time.Time {
+	return time.Unix(p.JoinTime, 0)
+}
+
+// Left returns when the participant left, or would have if they are still in
+// an ongoing meeting.
+func (p MeetingParticipant) Left() time.Time {
+	return time.Unix(p.JoinTime+int64(p.Duration), 0)
+}
+
+// RecordingTrack is a separately stored media track of a raw-tracks recording.
+type RecordingTrack struct {
+	S3Key         string `json:"s3Key"` // Identifies the track
+	Type          string `json:"type"`  // "audio" or "video"
+	Size          int64  `json:"size"`  // Bytes
+	ParticipantID string `json:"participantId,omitempty"`
 }
 
 // String returns a pointer to the string.
@@ -129,12 +603,52 @@ func Int32(i int32) *int32 {
 	return &i
 }
 
+// EjectAfter returns d in whole seconds, as expected by
+// RoomConfig.EjectAfterElapsed. It fails with ErrValidation if that is not
+// positive or overflows an int32, rather than leaving the field unset, which
+// would mean never ejecting.
+func EjectAfter(d time.Duration) (*int32, error) {
+	secs := d / time.Second
+	if secs <= 0 || secs > math.MaxInt32 {
+		return nil, Error{Message: fmt.Sprintf("%s: eject after %s is not between 1s and %ds", ErrValidation, d, math.MaxInt32)}
+	}
+	return Int32(int32(secs)), nil
+}
+
+// MustEjectAfter is like EjectAfter but panics if d is invalid, for durations
+// known to be valid such as constants.
+func MustEjectAfter(d time.Duration) *int32 {
+	secs, err := EjectAfter(d)
+	if err != nil {
+		panic(err)
+	}
+	return secs
+}
+
 // Timestamp returns number of seconds since epoch, consistent wih Daily's
 // API expectations.
 func Timestamp(t time.Time) *int64 {
 	return Int64(t.Unix())
 }
 
+// PermissionsList returns a pointer to a slice of the permission types.
+func PermissionsList(perms ...PermissionType) *[]PermissionType {
+	if perms == nil {
+		perms = []PermissionType{} // Sent as [], allowing nothing, rather than null
+	}
+	return &perms
+}
+
+// CanSend returns Permissions allowing only the given types to be sent.
+func CanSend(perms ...PermissionType) *Permissions {
+	return &Permissions{CanSend: PermissionsList(perms...)}
+}
+
+// Signaling returns a pointer to the SignalingType.
+func Signaling(t SignalingType) *SignalingType {
+	return &t
+}
+
 // Bool returns a pointer to the bool.
 func Bool(b bool) *bool {
 	return &b
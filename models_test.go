@@ -0,0 +1,272 @@
+package daily
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffRoomConfig(t *testing.T) {
+	tests := map[string]struct {
+		current, desired, want *RoomConfig
+	}{
+		"both nil":        {nil, nil, nil},
+		"nil desired":     {&RoomConfig{EnableChat: True()}, nil, nil},
+		"nil current":     {nil, &RoomConfig{EnableChat: True()}, &RoomConfig{EnableChat: True()}},
+		"empty desired":   {&RoomConfig{EnableChat: True()}, &RoomConfig{}, nil},
+		"same values":     {&RoomConfig{EnableChat: True(), Lang: String("en")}, &RoomConfig{EnableChat: True(), Lang: String("en")}, nil},
+		"changed value":   {&RoomConfig{MaxParticipants: Int32(10)}, &RoomConfig{MaxParticipants: Int32(20)}, &RoomConfig{MaxParticipants: Int32(20)}},
+		"newly set value": {&RoomConfig{}, &RoomConfig{Geo: String(GeoUSEast1)}, &RoomConfig{Geo: String(GeoUSEast1)}},
+		"unset in desired": {
+			&RoomConfig{EnableChat: True(), Lang: String("en")},
+			&RoomConfig{Lang: String("fr")},
+			&RoomConfig{Lang: String("fr")},
+		},
+		"only changes kept": {
+			&RoomConfig{EnableChat: True(), StartVideoOff: False(), ExpiresAt: Int64(100)},
+			&RoomConfig{EnableChat: True(), StartVideoOff: True(), ExpiresAt: Int64(200)},
+			&RoomConfig{StartVideoOff: True(), ExpiresAt: Int64(200)},
+		},
+		"nested struct equal": {
+			&RoomConfig{Permissions: CanSend(Video)},
+			&RoomConfig{Permissions: CanSend(Video)},
+			nil,
+		},
+		"nested struct changed": {
+			&RoomConfig{Permissions: CanSend(Video)},
+			&RoomConfig{Permissions: CanSend(Audio)},
+			&RoomConfig{Permissions: CanSend(Audio)},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := DiffRoomConfig(tt.current, tt.desired)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTimeWindow(t *testing.T) {
+	tests := map[string]struct {
+		nbf, exp *int64
+		valid    bool
+	}{
+		"nbf after exp":  {Int64(200), Int64(100), false},
+		"nbf equals exp": {Int64(100), Int64(100), false},
+		"nbf before exp": {Int64(100), Int64(200), true},
+		"only nbf":       {Int64(100), nil, true},
+		"only exp":       {nil, Int64(100), true},
+		"neither":        {nil, nil, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateTimeWindow(tt.nbf, tt.exp)
+			if tt.valid {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if e, ok := err.(Error); !ok || !strings.HasPrefix(e.Message, ErrInvalidTimeWindow) {
+				t.Errorf("err = %v, want ErrInvalidTimeWindow", err)
+			}
+		})
+	}
+}
+
+func TestEjectAfter(t *testing.T) {
+	tests := map[string]struct {
+		d    time.Duration
+		want int32
+	}{
+		"minutes":         {90 * time.Minute, 5400},
+		"truncated":       {1500 * time.Millisecond, 1},
+		"largest":         {math.MaxInt32 * time.Second, math.MaxInt32},
+		"negative":        {-time.Minute, 0},
+		"zero":            {0, 0},
+		"under a second":  {999 * time.Millisecond, 0},
+		"overflows int32": {(math.MaxInt32 + 1) * time.Second, 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := EjectAfter(tt.d)
+			if tt.want == 0 {
+				if e, ok := err.(Error); !ok || !strings.HasPrefix(e.Message, ErrValidation) || got != nil {
+					t.Errorf("got %v, %v, want ErrValidation", got, err)
+				}
+				return
+			}
+			if err != nil || got == nil || *got != tt.want {
+				t.Errorf("got %v, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustEjectAfterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic for a negative duration")
+		}
+	}()
+	MustEjectAfter(-time.Second)
+}
+
+func TestRoomExpiry(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	tests := map[string]struct {
+		room    Room
+		expired bool
+		left    time.Duration
+	}{
+		"no config":     {Room{}, false, 0},
+		"no expiry":     {Room{Config: &RoomConfig{}}, false, 0},
+		"future expiry": {Room{Config: &RoomConfig{ExpiresAt: Int64(now.Unix() + 90)}}, false, 90 * time.Second},
+		"expires now":   {Room{Config: &RoomConfig{ExpiresAt: Int64(now.Unix())}}, true, 0},
+		"past expiry":   {Room{Config: &RoomConfig{ExpiresAt: Int64(now.Unix() - 90)}}, true, 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.room.IsExpired(now); got != tt.expired {
+				t.Errorf("IsExpired = %v, want %v", got, tt.expired)
+			}
+			if got := tt.room.TimeUntilExpiry(now); got != tt.left {
+				t.Errorf("TimeUntilExpiry = %s, want %s", got, tt.left)
+			}
+		})
+	}
+}
+
+func TestRoomExpiresAt(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	tests := map[string]struct {
+		room Room
+		want time.Time
+		ok   bool
+	}{
+		"no config": {Room{}, time.Time{}, false},
+		"no expiry": {Room{Config: &RoomConfig{}}, time.Time{}, false},
+		"expired":   {Room{Config: &RoomConfig{ExpiresAt: Int64(now.Unix() - 60)}}, now.Add(-time.Minute), true},
+		"future":    {Room{Config: &RoomConfig{ExpiresAt: Int64(now.Unix() + 60)}}, now.Add(time.Minute), true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := tt.room.ExpiresAt()
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("ExpiresAt = %s, %v, want %s, %v", got, ok, tt.want, tt.ok)
+			}
+			// An expired room and one without an expiry both have no time
+			// left, so only ExpiresAt tells them apart.
+			if !tt.ok && tt.room.IsExpired(now) {
+				t.Error("room without an expiry reported as expired")
+			}
+		})
+	}
+}
+
+func TestRecordingStatus(t *testing.T) {
+	tests := []struct {
+		json     string
+		want     RecordingStatus
+		terminal bool
+	}{
+		{`"in-progress"`, RecordingInProgress, false},
+		{`"finished"`, RecordingFinished, true},
+		{`"canceled"`, RecordingCanceled, true},
+		{`"uploading"`, RecordingStatus("uploading"), false},
+	}
+	for _, tt := range tests {
+		var r Recording
+		if err := json.Unmarshal([]byte(`{"id":"r","status":`+tt.json+`}`), &r); err != nil {
+			t.Fatalf("unmarshaling status %s: %v", tt.json, err)
+		}
+		if r.Status != tt.want {
+			t.Errorf("status %s = %q, want %q", tt.json, r.Status, tt.want)
+		}
+		if r.Status.IsTerminal() != tt.terminal {
+			t.Errorf("%q.IsTerminal() = %v, want %v", r.Status, !tt.terminal, tt.terminal)
+		}
+	}
+}
+
+func TestRecordingShareURL(t *testing.T) {
+	tests := map[string]struct {
+		token string
+		want  string
+	}{
+		"no token": {"", ""},
+		"token":    {"abc123", "https://example.daily.co/recordings/abc123"},
+		"escaped":  {"a/b c", "https://example.daily.co/recordings/a%2Fb%20c"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &Recording{ShareToken: tt.token}
+			if got := r.ShareURL("example"); got != tt.want {
+				t.Errorf("ShareURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionsBuilder(t *testing.T) {
+	perms, err := NewPermissions().AllowSend(Audio).AllowSend(Video).WithPresence(false).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	want := &Permissions{CanSend: &[]PermissionType{Audio, Video}, HasPresence: Bool(false)}
+	if !reflect.DeepEqual(perms, want) {
+		t.Errorf("Build = %+v, want %+v", perms, want)
+	}
+	data, _ := json.Marshal(perms)
+	if want := `{"canSend":["audio","video"],"hasPresence":false}`; string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+
+	_, err = NewPermissions().AllowSend(Audio, PermissionType("hologram")).Build()
+	if e, ok := err.(Error); !ok || !strings.HasPrefix(e.Message, ErrValidation) || !strings.Contains(e.Message, "hologram") {
+		t.Errorf("Build with an unknown type: err = %v, want %s naming it", err, ErrValidation)
+	}
+}
+
+func TestValidateTokenAgainstRoom(t *testing.T) {
+	broadcast := &Room{Name: "town-hall", Config: &RoomConfig{OwnerOnlyBroadcast: True()}}
+	tests := map[string]struct {
+		room  *Room
+		token *MeetingToken
+		want  []string
+	}{
+		"non-owner may send": {
+			broadcast,
+			&MeetingToken{RoomName: String("town-hall"), Permissions: CanSend(Audio, ScreenVideo)},
+			[]string{"room only lets owners broadcast, but non-owner token can send audio"},
+		},
+		"owner may send": {
+			broadcast,
+			&MeetingToken{RoomName: String("town-hall"), IsOwner: True(), Permissions: CanSend(Audio, Video)},
+			nil,
+		},
+		"non-owner can't send": {
+			broadcast,
+			&MeetingToken{RoomName: String("town-hall"), Permissions: CanSend()},
+			nil,
+		},
+		"other room": {
+			&Room{Name: "standup"},
+			&MeetingToken{RoomName: String("town-hall")},
+			[]string{"token is for room town-hall, not standup"},
+		},
+		"nil token": {broadcast, nil, nil},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ValidateTokenAgainstRoom(tt.room, tt.token); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
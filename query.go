@@ -0,0 +1,40 @@
+package daily
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// queryValues encodes the fields of the struct v which have a `url` tag as
+// query parameters. Fields tagged with omitempty are left out when zero.
+func queryValues(v interface{}) url.Values {
+	q := url.Values{}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		tag := rt.Field(i).Tag.Get("url")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+		fv := rv.Field(i)
+		if opts == "omitempty" && fv.IsZero() {
+			continue
+		}
+		q.Set(name, fmt.Sprint(fv.Interface()))
+	}
+	return q
+}
+
+// withQuery appends q to path as its query string, if q isn't empty.
+func withQuery(path string, q url.Values) string {
+	if len(q) == 0 {
+		return path
+	}
+	return path + "?" + q.Encode()
+}
@@ -0,0 +1,61 @@
+package daily
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryValues(t *testing.T) {
+	tests := map[string]struct {
+		params interface{}
+		want   url.Values
+	}{
+		"empty recordings params": {GetRecordingsParams{}, url.Values{}},
+		"full recordings params": {
+			GetRecordingsParams{Limit: 10, EndingBefore: "a", StartingAfter: "b", RoomName: "room"},
+			url.Values{"limit": {"10"}, "ending_before": {"a"}, "starting_after": {"b"}, "room_name": {"room"}},
+		},
+		"empty rooms params": {ListRoomsRequest{}, url.Values{}},
+		"rooms params skip untagged": {
+			ListRoomsRequest{Limit: 5, EndingAfter: "ignored"},
+			url.Values{"limit": {"5"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := queryValues(tt.params); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryStrings(t *testing.T) {
+	var queries []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte(`{}`))
+	})
+	ctx := context.Background()
+
+	c.ListRooms(ctx, nil)
+	c.ListRooms(ctx, &ListRoomsRequest{Limit: 5, EndingAfter: "room-id"})
+	c.GetRecordingLinkWithTTL(ctx, "rec", 0)
+	c.GetRecordingLinkWithTTL(ctx, "rec", 90*time.Minute)
+	c.GetTrackDownloadLink(ctx, "rec", "track a")
+
+	want := []string{
+		"",
+		"limit=5&starting_after=room-id",
+		"",
+		"valid_for_secs=5400",
+		"track=track+a",
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %q, want %q", queries, want)
+	}
+}
@@ -1,11 +1,21 @@
 package daily
 
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
 // ListRoomsRequest contains the parameters for listing rooms.
 // https://docs.daily.co/reference#list-rooms
 type ListRoomsRequest struct {
-	Limit        int32  `json:"limit,omitempty"`
-	EndingBefore string `json:"ending_before,omitempty"`
-	EndingAfter  string `json:"ending_after,omitempty"`
+	Limit         int32  `url:"limit,omitempty"`
+	EndingBefore  string `url:"ending_before,omitempty"`
+	StartingAfter string `url:"starting_after,omitempty"`
+
+	// Deprecated: Daily has no ending_after parameter. It is sent as
+	// StartingAfter when that isn't set.
+	EndingAfter string `url:"-"`
 }
 
 // ListRoomsResponse is the response envelope when listing rooms.
@@ -15,6 +25,28 @@ type ListRoomsResponse struct {
 	Rooms      []Room `json:"data"`
 }
 
+func (r *ListRoomsResponse) decodeStream(dec *json.Decoder, strict bool) error {
+	return decodeList(dec, strict, "data", &r.Rooms, map[string]interface{}{"total_count": &r.TotalCount})
+}
+
+// NextCursor returns the value to pass as ListRoomsRequest.StartingAfter to get
+// the next page, or "" if the page is empty.
+func (r *ListRoomsResponse) NextCursor() string {
+	if len(r.Rooms) == 0 {
+		return ""
+	}
+	return r.Rooms[len(r.Rooms)-1].ID
+}
+
+// PrevCursor returns the value to pass as ListRoomsRequest.EndingBefore to get
+// the previous page, or "" if the page is empty.
+func (r *ListRoomsResponse) PrevCursor() string {
+	if len(r.Rooms) == 0 {
+		return ""
+	}
+	return r.Rooms[0].ID
+}
+
 // CreateRoomRequest contains the parameters for creating a room.
 // https://docs.daily.co/reference#create-room
 type CreateRoomRequest struct {
@@ -23,6 +55,13 @@ type CreateRoomRequest struct {
 	Config  *RoomConfig `json:"properties,omitempty"`
 }
 
+func (r *CreateRoomRequest) validate() error {
+	if r == nil {
+		return nil
+	}
+	return r.Config.validate()
+}
+
 // CreateRoomResponse contains the newly created room.
 type CreateRoomResponse struct {
 	Room
@@ -33,22 +72,49 @@ type GetRoomResponse struct {
 	Room
 }
 
-// UpdateRoomRequest contains the parameters for updating a room.
+// UpdateRoomRequest contains the parameters for updating a room. Rooms can't be
+// renamed, see Client.RecreateRoom.
 type UpdateRoomRequest struct {
 	Privacy RoomPrivacy `json:"privacy,omitempty"`
 	Config  *RoomConfig `json:"properties,omitempty"`
 }
 
+func (r *UpdateRoomRequest) validate() error {
+	if r == nil {
+		return nil
+	}
+	return r.Config.validate()
+}
+
 // UpdateRoomResponse contains the updated room.
 type UpdateRoomResponse struct {
 	Room
 }
 
+// GetRoomPresenceResponse contains the participants currently in a room.
+type GetRoomPresenceResponse struct {
+	TotalCount   int32                 `json:"total_count"`
+	Participants []PresenceParticipant `json:"data"`
+}
+
+// SendAppMessageRequest is an app message sent to participants of a room.
+type SendAppMessageRequest struct {
+	Data      json.RawMessage `json:"data"`
+	Recipient string          `json:"recipient"` // A participant's session ID, or "*" for everyone
+}
+
 // CreateMeetingTokenRequest contains the properties for creating a meeting token.
 type CreateMeetingTokenRequest struct {
 	Properties *MeetingToken `json:"properties,omitempty"`
 }
 
+func (r *CreateMeetingTokenRequest) validate() error {
+	if r == nil || r.Properties == nil {
+		return nil
+	}
+	return validateTimeWindow(r.Properties.NotBefore, r.Properties.ExpiresAt)
+}
+
 // CreateMeetingTokenResponse contains newly-created meeting token string.
 type CreateMeetingTokenResponse struct {
 	Token *string `json:"token,omitempty"`
@@ -57,16 +123,48 @@ type CreateMeetingTokenResponse struct {
 // GetMeetingTokenResponse contains the meeting token properties directly (nil if invalid token)
 type GetMeetingTokenResponse MeetingToken
 
+// TokenInspection is the result of cross-checking a meeting token against the
+// room it is for. Issues lists human-readable inconsistencies, if any.
+type TokenInspection struct {
+	Token  *GetMeetingTokenResponse
+	Room   *Room // nil if the token has no room or the room doesn't exist
+	Issues []string
+}
+
 type GetRecordingResponse struct {
 	TotalCount int         `json:"total_count"`
 	Recording  []Recording `json:"data"`
 }
 
+func (r *GetRecordingResponse) decodeStream(dec *json.Decoder, strict bool) error {
+	return decodeList(dec, strict, "data", &r.Recording, map[string]interface{}{"total_count": &r.TotalCount})
+}
+
 type GetRecordingLinkResponse struct {
 	DownloadLink string `json:"download_link"`
 	Expires      int    `json:"expires"`
 }
 
+// RecordingLink is a parsed recording download link.
+type RecordingLink struct {
+	URL       *url.URL
+	ExpiresAt time.Time
+}
+
+// IsExpired reports whether the link has expired at now.
+func (l *RecordingLink) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// RecordingLink parses the response into a RecordingLink.
+func (r *GetRecordingLinkResponse) RecordingLink() (*RecordingLink, error) {
+	u, err := url.Parse(r.DownloadLink)
+	if err != nil {
+		return nil, Error{Message: ErrParseError + ": " + err.Error(), RawDetails: r.DownloadLink}
+	}
+	return &RecordingLink{URL: u, ExpiresAt: time.Unix(int64(r.Expires), 0)}, nil
+}
+
 type StartRecordingRequest struct {
 	Height int    `json:"height"`
 	Width  int    `json:"width"`
@@ -77,3 +175,33 @@ type StartRecordingResponse struct {
 	Sent        bool   `json:"sent"`
 	RecordingID string `json:"recordingId"`
 }
+
+// GetMeetingsResponse is the response envelope when listing meeting sessions.
+type GetMeetingsResponse struct {
+	TotalCount int              `json:"total_count"`
+	Meetings   []MeetingSession `json:"data"`
+}
+
+func (r *GetMeetingsResponse) decodeStream(dec *json.Decoder, strict bool) error {
+	return decodeList(dec, strict, "data", &r.Meetings, map[string]interface{}{"total_count": &r.TotalCount})
+}
+
+// UsageResponse summarizes a room's usage over a timeframe.
+type UsageResponse struct {
+	Sessions           int     `json:"sessions"`
+	TotalMinutes       float64 `json:"total_minutes"`
+	ParticipantMinutes float64 `json:"participant_minutes"`
+}
+
+// ProbeResult is the outcome of Client.Probe.
+type ProbeResult struct {
+	StatusCode int           // 0 if no response was received
+	Latency    time.Duration // Round trip time of the probe request
+	RateLimit  *RateLimit    // nil if the response had no rate limit headers
+}
+
+// RateLimit is a snapshot of the rate limit reported in response headers.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+}
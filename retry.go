@@ -0,0 +1,261 @@
+package daily
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// WithRetries retries requests which were rate limited (429), and idempotent
+// requests which hit a server error (5xx), making up to maxAttempts attempts
+// with exponential backoff. A Retry-After header overrides the backoff.
+//
+// If the next backoff would outlast the context's deadline, the last response
+// is returned straight away instead of sleeping into a certain cancellation.
+// Set RetryPolicy.OnGiveUp with WithRetryPolicy to observe when that happens.
+func WithRetries(maxAttempts int) Option {
+	return func(c *Client) {
+		cfg := c.retryConfig()
+		cfg.MaxAttempts = maxAttempts
+		cfg.statusRetries = true
+	}
+}
+
+// WithNetworkRetries retries idempotent requests which failed with a transient
+// network error, such as a timeout or a reset connection. It is independent of
+// WithRetries, which sets the number of attempts (3 by default). Requests
+// whose body can't be rewound are never retried, and nor are POSTs: a create
+// which timed out may still have succeeded, and Daily doesn't document
+// honouring an Idempotency-Key. POSTs are still retried on 429s by
+// WithRetries, as rate-limited requests aren't processed.
+func WithNetworkRetries() Option {
+	return func(c *Client) {
+		c.retryConfig().networkRetries = true
+	}
+}
+
+// RetryPolicy controls the backoff between retries enabled by WithRetryPolicy.
+// The delay before the nth retry is BaseDelay * Multiplier^(n-1), capped at
+// MaxDelay, then randomized according to Jitter.
+type RetryPolicy struct {
+	MaxAttempts int // Including the first attempt
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration // Zero or less means no cap
+	Multiplier  float64       // Values below 1 are treated as 1
+	Jitter      Jitter
+
+	// OnGiveUp, if set, is called when a request could be retried but the
+	// next backoff would outlast the context's deadline, so the last
+	// response or error is returned instead. attempt is the number of
+	// attempts made and delay the backoff which didn't fit.
+	OnGiveUp func(req *http.Request, attempt int, delay time.Duration)
+}
+
+// Jitter is a strategy for randomizing retry delays, which spreads out the
+// retries of clients which failed at the same time.
+type Jitter int
+
+const (
+	NoJitter    Jitter = iota // Use the delay as is
+	FullJitter                // Pick uniformly from [0, delay]
+	EqualJitter               // Pick uniformly from [delay/2, delay]
+)
+
+// DefaultRetryPolicy returns the policy used by WithRetries: 3 attempts, with
+// delays doubling from 500ms up to 10s and no jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Multiplier:  2,
+		Jitter:      NoJitter,
+	}
+}
+
+// WithRetryPolicy retries the same requests as WithRetries, with the attempts
+// and backoff set by p. A Retry-After header still overrides the backoff.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		cfg := c.retryConfig()
+		cfg.RetryPolicy = p
+		cfg.statusRetries = true
+	}
+}
+
+type retryConfig struct {
+	RetryPolicy
+	statusRetries  bool
+	networkRetries bool
+	sleep          func(ctx context.Context, d time.Duration) error
+}
+
+// retryConfig returns the client's retry config, adding the retry layer the
+// first time.
+func (c *Client) retryConfig() *retryConfig {
+	if c.retry == nil {
+		cfg := &retryConfig{RetryPolicy: DefaultRetryPolicy(), sleep: sleep}
+		c.retry = cfg
+		c.layers[retryLayer] = func(hc httpClient) httpClient {
+			return &retryClient{httpClient: hc, cfg: cfg}
+		}
+	}
+	return c.retry
+}
+
+type retryClient struct {
+	httpClient
+	cfg *retryConfig
+}
+
+func (r *retryClient) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := r.httpClient.Do(req)
+		if attempt >= r.cfg.MaxAttempts || !canResend(req) {
+			return resp, err
+		}
+		if err != nil {
+			if !r.cfg.networkRetries || ctx.Err() != nil || !isTransient(err) || !isIdempotent(req) {
+				return resp, err
+			}
+		} else if !r.cfg.statusRetries || !shouldRetry(req, resp) {
+			return resp, nil
+		}
+
+		delay := r.backoff(attempt, resp)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			if r.cfg.OnGiveUp != nil {
+				r.cfg.OnGiveUp(req, attempt, delay)
+			}
+			return resp, err
+		}
+		next := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			next.Body = body
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		req = next
+
+		if err := r.cfg.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for d, returning early with the context's error if it is
+// canceled first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// backoff returns how long to wait before retrying after the given attempt.
+// resp is nil after a network error.
+func (r *retryClient) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	mult := r.cfg.Multiplier
+	if mult < 1 {
+		mult = 1
+	}
+	maxDelay := r.cfg.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = math.MaxInt64
+	}
+	delay := r.cfg.BaseDelay
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		next := float64(delay) * mult
+		if next >= float64(maxDelay) {
+			delay = maxDelay
+			break
+		}
+		delay = time.Duration(next)
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	switch r.cfg.Jitter {
+	case FullJitter:
+		delay = time.Duration(jitterFloat() * float64(delay))
+	case EqualJitter:
+		delay = delay/2 + time.Duration(jitterFloat()*float64(delay/2))
+	}
+	return delay
+}
+
+var jitterRand = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// jitterFloat returns a random number in [0, 1). Unlike the math/rand global
+// source it is seeded, so clients don't all pick the same delays.
+func jitterFloat() float64 {
+	jitterRand.Lock()
+	defer jitterRand.Unlock()
+	return jitterRand.Float64()
+}
+
+// canResend reports whether req's body, if any, can be rewound to send again.
+func canResend(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// shouldRetry reports whether resp may succeed if req is sent again. Server
+// errors are only retried when repeating req can't have extra side effects.
+func shouldRetry(req *http.Request, resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= 500:
+		return isIdempotent(req)
+	}
+	return false
+}
+
+// isTransient reports whether err is a network error which may not recur.
+func isTransient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// isIdempotent reports whether sending req twice has the same effect as once.
+// POSTs never are, even with an Idempotency-Key, as Daily doesn't document
+// honouring that header.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
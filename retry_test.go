@@ -0,0 +1,291 @@
+package daily
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryGivesUpWhenBackoffOutlastsDeadline(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":"rate-limit"}`))
+	}, WithRetries(3))
+	c.retry.sleep = sleep
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := c.GetRoom(ctx, "room")
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("took %s, want an immediate return", elapsed)
+	}
+	if e, ok := err.(Error); !ok || e.Message != ErrTooManyRequests {
+		t.Fatalf("err = %v, want ErrTooManyRequests", err)
+	}
+	if calls != 1 {
+		t.Errorf("got %d attempts, want 1", calls)
+	}
+}
+
+func TestRetryOnGiveUp(t *testing.T) {
+	type giveUp struct {
+		path    string
+		attempt int
+		delay   time.Duration
+	}
+	var got []giveUp
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":"rate-limit"}`))
+	}, WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		OnGiveUp: func(req *http.Request, attempt int, delay time.Duration) {
+			got = append(got, giveUp{req.URL.Path, attempt, delay})
+		},
+	}))
+
+	// With room for the backoffs every attempt is made and the hook isn't
+	// called.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := c.GetRoom(ctx, "room"); err == nil {
+		t.Fatal("GetRoom succeeded, want ErrTooManyRequests")
+	}
+	if len(got) != 0 {
+		t.Fatalf("OnGiveUp called %d times with a long deadline, want 0", len(got))
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := c.GetRoom(ctx, "room"); err == nil {
+		t.Fatal("GetRoom succeeded, want ErrTooManyRequests")
+	}
+	want := []giveUp{{"/v1/rooms/room", 1, 5 * time.Second}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OnGiveUp calls = %+v, want %+v", got, want)
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	tests := map[string]struct {
+		call func(*Client, context.Context) error
+		want int32
+	}{
+		"GET": {func(c *Client, ctx context.Context) error {
+			_, err := c.GetRoom(ctx, "room")
+			return err
+		}, 3},
+		"POST with idempotency key": {func(c *Client, ctx context.Context) error {
+			_, err := c.CreateRoom(WithIdempotencyKey(ctx, "key"), &CreateRoomRequest{})
+			return err
+		}, 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":"server-error"}`))
+			}, WithRetries(3))
+
+			err := tt.call(c, context.Background())
+			if e, ok := err.(Error); !ok || e.Message != ErrInternal {
+				t.Fatalf("err = %v, want ErrInternal", err)
+			}
+			if calls != tt.want {
+				t.Errorf("got %d attempts, want %d", calls, tt.want)
+			}
+		})
+	}
+}
+
+// flakyClient fails its first request with a connection reset, then answers
+// every request with body.
+type flakyClient struct {
+	calls int
+	body  string
+}
+
+func (f *flakyClient) Do(req *http.Request) (*http.Response, error) {
+	f.calls++
+	if f.calls == 1 {
+		return nil, syscall.ECONNRESET
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(f.body)),
+	}, nil
+}
+
+func TestNetworkRetries(t *testing.T) {
+	tests := map[string]struct {
+		opts      []Option
+		call      func(*Client) error
+		wantCalls int
+		wantErr   bool
+	}{
+		"GET retried": {
+			opts: []Option{WithNetworkRetries()},
+			call: func(c *Client) error {
+				_, err := c.GetRoom(context.Background(), "room")
+				return err
+			},
+			wantCalls: 2,
+		},
+		"POST not retried": {
+			opts: []Option{WithNetworkRetries()},
+			call: func(c *Client) error {
+				_, err := c.CreateRoom(WithIdempotencyKey(context.Background(), "key"), &CreateRoomRequest{})
+				return err
+			},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		"disabled with status retries on": {
+			opts: []Option{WithRetries(3)},
+			call: func(c *Client) error {
+				_, err := c.GetRoom(context.Background(), "room")
+				return err
+			},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			flaky := &flakyClient{body: `{"name":"room"}`}
+			c := New(tt.opts...)
+			c.HTTPClient = flaky
+			c.retry.sleep = func(context.Context, time.Duration) error { return nil }
+
+			err := tt.call(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error: %t", err, tt.wantErr)
+			}
+			if flaky.calls != tt.wantCalls {
+				t.Errorf("got %d attempts, want %d", flaky.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := map[string]struct {
+		policy RetryPolicy
+		want   []time.Duration
+	}{
+		"default": {DefaultRetryPolicy(), []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second}},
+		"capped": {
+			RetryPolicy{BaseDelay: time.Second, MaxDelay: 3 * time.Second, Multiplier: 2},
+			[]time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+		"zero max delay is uncapped": {
+			RetryPolicy{BaseDelay: time.Second, Multiplier: 2},
+			[]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		"multiplier below 1": {
+			RetryPolicy{BaseDelay: time.Second, Multiplier: 0.5},
+			[]time.Duration{time.Second, time.Second, time.Second},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := &retryClient{cfg: &retryConfig{RetryPolicy: tt.policy}}
+			for i, want := range tt.want {
+				if got := r.backoff(i+1, nil); got != want {
+					t.Errorf("attempt %d: backoff = %s, want %s", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	tests := map[Jitter]time.Duration{
+		FullJitter:  0,
+		EqualJitter: 4 * time.Second,
+	}
+	for jitter, min := range tests {
+		r := &retryClient{cfg: &retryConfig{RetryPolicy: RetryPolicy{BaseDelay: 8 * time.Second, Jitter: jitter}}}
+		for i := 0; i < 100; i++ {
+			if got := r.backoff(1, nil); got < min || got > 8*time.Second {
+				t.Fatalf("jitter %d: backoff = %s, want within [%s, 8s]", jitter, got, min)
+			}
+		}
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":"rate-limit"}`))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, Multiplier: 2}))
+	var delays []time.Duration
+	c.retry.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	if _, err := c.GetRoom(context.Background(), "room"); err == nil {
+		t.Fatal("GetRoom succeeded, want ErrTooManyRequests")
+	}
+	if calls != 4 {
+		t.Errorf("got %d attempts, want 4", calls)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if !reflect.DeepEqual(delays, want) {
+		t.Errorf("slept %v, want %v", delays, want)
+	}
+}
+
+func TestRetriesIgnoreClientClock(t *testing.T) {
+	clock := newTestClock()
+	clock.Advance(24 * time.Hour * 365 * 100)
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"unavailable"}`))
+	}, WithClock(clock.Now), WithRetries(3))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	c.GetRoom(ctx, "room")
+	if calls != 3 {
+		t.Errorf("got %d attempts, want 3: a fake clock mustn't affect deadline checks", calls)
+	}
+}
+
+func TestRetryRateLimitedPOST(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":"rate-limit"}`))
+			return
+		}
+		w.Write([]byte(`{"name":"room"}`))
+	}, WithRetries(3))
+
+	if _, err := c.CreateRoom(context.Background(), &CreateRoomRequest{}); err != nil {
+		t.Fatalf("CreateRoom: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("got %d attempts, want 3", calls)
+	}
+}
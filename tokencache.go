@@ -0,0 +1,137 @@
+package daily
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// WithTokenCache caches up to size GetMeetingToken results for ttl, so
+// repeatedly validating the same token doesn't call the API each time.
+// Entries are dropped early once the token itself expires. Expired entries are
+// purged in the background until the client is closed. Passing it again
+// replaces the previous cache.
+func WithTokenCache(size int, ttl time.Duration) Option {
+	return func(c *Client) {
+		if c.tokenCache != nil {
+			c.tokenCache.stop()
+		}
+		c.tokenCache = newTokenCache(size, ttl)
+		go c.tokenCache.janitor(func() time.Time { return c.now() })
+	}
+}
+
+// tokenCache is an LRU cache of meeting token properties with a TTL.
+type tokenCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	lru   *list.List // Most recently used at the front
+	items map[string]*list.Element
+
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+type tokenCacheEntry struct {
+	token   string
+	props   GetMeetingTokenResponse
+	expires time.Time
+}
+
+func newTokenCache(size int, ttl time.Duration) *tokenCache {
+	return &tokenCache{
+		size:  size,
+		ttl:   ttl,
+		lru:   list.New(),
+		items: map[string]*list.Element{},
+		done:  make(chan struct{}),
+	}
+}
+
+// janitor periodically purges expired entries until stop is called.
+func (tc *tokenCache) janitor(now func() time.Time) {
+	interval := tc.ttl
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-tc.done:
+			return
+		case <-ticker.C:
+			tc.purge(now())
+		}
+	}
+}
+
+// stop ends the janitor. It is safe to call more than once.
+func (tc *tokenCache) stop() {
+	tc.stopOnce.Do(func() { close(tc.done) })
+}
+
+func (tc *tokenCache) purge(now time.Time) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	for el := tc.lru.Front(); el != nil; {
+		next := el.Next()
+		if !now.Before(el.Value.(*tokenCacheEntry).expires) {
+			tc.remove(el)
+		}
+		el = next
+	}
+}
+
+func (tc *tokenCache) get(token string, now time.Time) (*GetMeetingTokenResponse, bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	el, ok := tc.items[token]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*tokenCacheEntry)
+	if !now.Before(entry.expires) {
+		tc.remove(el)
+		return nil, false
+	}
+	tc.lru.MoveToFront(el)
+	props := entry.props
+	return &props, true
+}
+
+func (tc *tokenCache) add(token string, props *GetMeetingTokenResponse, now time.Time) {
+	if tc.size < 1 {
+		return
+	}
+	expires := now.Add(tc.ttl)
+	if props.ExpiresAt != nil {
+		if exp := time.Unix(*props.ExpiresAt, 0); exp.Before(expires) {
+			expires = exp
+		}
+	}
+	if !now.Before(expires) {
+		return
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if el, ok := tc.items[token]; ok {
+		tc.remove(el)
+	}
+	tc.items[token] = tc.lru.PushFront(&tokenCacheEntry{
+		token:   token,
+		props:   *props,
+		expires: expires,
+	})
+	for tc.lru.Len() > tc.size {
+		tc.remove(tc.lru.Back())
+	}
+}
+
+// remove drops el from the cache. The caller must hold mu.
+func (tc *tokenCache) remove(el *list.Element) {
+	tc.lru.Remove(el)
+	delete(tc.items, el.Value.(*tokenCacheEntry).token)
+}
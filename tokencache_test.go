@@ -0,0 +1,119 @@
+package daily
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer serves GetMeetingToken with the given exp, counting calls.
+func tokenServer(calls *int32, exp int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		fmt.Fprintf(w, `{"room_name":"room","exp":%d}`, exp)
+	}
+}
+
+func TestTokenCacheServesRepeatValidations(t *testing.T) {
+	clock := newTestClock()
+	var calls int32
+	exp := clock.Now().Add(time.Hour).Unix()
+	c := newTestClient(t, tokenServer(&calls, exp), WithClock(clock.Now), WithTokenCache(10, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.GetMeetingToken(context.Background(), "token")
+		if err != nil {
+			t.Fatalf("GetMeetingToken: %v", err)
+		}
+		if resp.RoomName == nil || *resp.RoomName != "room" {
+			t.Fatalf("RoomName = %v, want room", resp.RoomName)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("got %d HTTP calls, want 1", calls)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := c.GetMeetingToken(context.Background(), "token"); err != nil {
+		t.Fatalf("GetMeetingToken: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d HTTP calls after TTL, want 2", calls)
+	}
+}
+
+func TestTokenCacheBypassesExpiredTokens(t *testing.T) {
+	clock := newTestClock()
+	var calls int32
+	exp := clock.Now().Add(10 * time.Second).Unix()
+	c := newTestClient(t, tokenServer(&calls, exp), WithClock(clock.Now), WithTokenCache(10, time.Hour))
+
+	if _, err := c.GetMeetingToken(context.Background(), "token"); err != nil {
+		t.Fatalf("GetMeetingToken: %v", err)
+	}
+	clock.Advance(10 * time.Second)
+	if _, err := c.GetMeetingToken(context.Background(), "token"); err != nil {
+		t.Fatalf("GetMeetingToken: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d HTTP calls, want 2: the token expired before the cache TTL", calls)
+	}
+}
+
+func TestTokenCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	tc := newTokenCache(2, time.Hour)
+	now := time.Unix(1600000000, 0)
+	tc.add("a", &GetMeetingTokenResponse{}, now)
+	tc.add("b", &GetMeetingTokenResponse{}, now)
+	tc.get("a", now)
+	tc.add("c", &GetMeetingTokenResponse{}, now)
+
+	if _, ok := tc.get("b", now); ok {
+		t.Error("b is cached, want it evicted")
+	}
+	for _, token := range []string{"a", "c"} {
+		if _, ok := tc.get(token, now); !ok {
+			t.Errorf("%s is not cached", token)
+		}
+	}
+}
+
+func TestCloseStopsJanitor(t *testing.T) {
+	before := runtime.NumGoroutine()
+	c := New(WithTokenCache(10, time.Minute))
+	if runtime.NumGoroutine() <= before {
+		t.Fatal("no janitor goroutine started")
+	}
+
+	c.Close()
+	c.Close()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines after Close, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWithTokenCacheReplacesCache(t *testing.T) {
+	before := runtime.NumGoroutine()
+	c := New(WithTokenCache(10, time.Minute), WithTokenCache(20, time.Minute))
+	if c.tokenCache.size != 20 {
+		t.Errorf("cache size = %d, want the last option's 20", c.tokenCache.size)
+	}
+
+	// Only the replacement's janitor should still be running.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before+1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines running, want %d", runtime.NumGoroutine(), before+1)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	c.Close()
+}